@@ -1,7 +1,11 @@
 package main
 
-import "github.com/baepo-cloud/baepo-cli/pkg/baepocmd"
+import (
+	"os"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/baepocmd"
+)
 
 func main() {
-	baepocmd.Main()
+	os.Exit(int(baepocmd.Main()))
 }
@@ -0,0 +1,92 @@
+// Package alias expands the user defined aliases of the config file.
+//
+// An alias expands to a baepo command line, in which $1, $2... are replaced by
+// the arguments given to the alias and $@ by all of them. Arguments not
+// referenced are appended. Aliases starting with ! are run by the shell
+// instead, with the arguments given as $1, $2... and $@.
+package alias
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+)
+
+// ShellPrefix marks aliases run by the shell.
+const ShellPrefix = "!"
+
+var placeholder = regexp.MustCompile(`\$(\d+|@)`)
+
+// IsShell reports whether expansion is run by the shell.
+func IsShell(expansion string) bool {
+	return strings.HasPrefix(expansion, ShellPrefix)
+}
+
+// Expand returns the arguments of the baepo command line expansion stands for
+// when called with args.
+func Expand(expansion string, args []string) ([]string, error) {
+	words, err := shellwords.Split(expansion)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make([]bool, len(args))
+	allUsed := false
+	var expanded []string
+	for _, word := range words {
+		if word == "$@" {
+			expanded = append(expanded, args...)
+			allUsed = true
+			continue
+		}
+
+		var missing int
+		word = placeholder.ReplaceAllStringFunc(word, func(m string) string {
+			if m == "$@" {
+				allUsed = true
+				return strings.Join(args, " ")
+			}
+			n, _ := strconv.Atoi(m[1:])
+			if n < 1 || n > len(args) {
+				missing = max(missing, n)
+				return m
+			}
+			used[n-1] = true
+			return args[n-1]
+		})
+		if missing > 0 {
+			return nil, fmt.Errorf("expected at least %d argument(s), got %d", missing, len(args))
+		}
+		expanded = append(expanded, word)
+	}
+
+	if !allUsed {
+		for i, arg := range args {
+			if !used[i] {
+				expanded = append(expanded, arg)
+			}
+		}
+	}
+
+	return expanded, nil
+}
+
+// ShellCommand returns the command running the shell alias expansion with
+// args. The arguments are given to the shell as positional parameters rather
+// than substituted, so that they need no quoting.
+func ShellCommand(expansion string, args []string, env []string) *exec.Cmd {
+	script := strings.TrimPrefix(expansion, ShellPrefix)
+
+	// sh rather than $SHELL, as aliases are written for a POSIX shell.
+	cmd := exec.Command("sh", append([]string{"-c", script, "baepo"}, args...)...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
@@ -0,0 +1,60 @@
+package alias_test
+
+import (
+	"bytes"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/alias"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		expansion string
+		args      []string
+		expected  []string
+	}{
+		{"machine list --state running", nil, []string{"machine", "list", "--state", "running"}},
+		{"machine list", []string{"--json"}, []string{"machine", "list", "--json"}},
+		{"machine logs $1 --follow", []string{"m1", "--json"}, []string{"machine", "logs", "m1", "--follow", "--json"}},
+		{"machine start $@ --wait", []string{"m1", "m2"}, []string{"machine", "start", "m1", "m2", "--wait"}},
+		{"machine update $2 --name=$1", []string{"web", "m1"}, []string{"machine", "update", "m1", "--name=web"}},
+		{`machine create --env "GREETING=$1 world"`, []string{"hello"}, []string{"machine", "create", "--env", "GREETING=hello world"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expansion, func(t *testing.T) {
+			got, err := alias.Expand(tt.expansion, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExpandMissingArgument(t *testing.T) {
+	if _, err := alias.Expand("machine update $1 --name $2", []string{"m1"}); err == nil {
+		t.Error("Expected an error for a missing argument")
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+
+	var stdout bytes.Buffer
+	cmd := alias.ShellCommand(`!printf '%s|' "$1" "$@"`, []string{"a b", "c"}, nil)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "a b|a b|c|"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
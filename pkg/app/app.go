@@ -1,10 +1,11 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
 	"fmt"
-	"net/http"
+	"io"
 
 	"connectrpc.com/connect"
 	"github.com/baepo-cloud/baepo-cli/pkg/config"
@@ -14,36 +15,79 @@ import (
 
 const (
 	ctxKey = "baepo-cli"
+
+	// DryRunAnnotation marks the commands that honor --dry-run. The flag is
+	// rejected by every other command.
+	DryRunAnnotation = "dry-run"
 )
 
 type App struct {
 	Config   *config.Config
 	IOStream *iostream.IOStream
 
+	// DryRun asks commands to print the requests and config changes they would
+	// make instead of sending or saving them.
+	DryRun bool
+
+	// Workspace overrides the workspace of the current context for a single
+	// command, see WorkspaceID.
+	Workspace string
+
+	// Trace is where API calls are traced, nil when tracing is off. It is
+	// closed by Close.
+	Trace io.WriteCloser
+
 	AuthClient    apiv1pbconnect.AuthServiceClient
 	UserClient    apiv1pbconnect.UserServiceClient
 	MachineClient apiv1pbconnect.MachineServiceClient
 }
 
-func NewApp(cfg *config.Config, ioStream *iostream.IOStream) *App {
+// NewApp builds the service clients of the current context on httpClient. opts
+// are applied to every client, after authentication.
+func NewApp(cfg *config.Config, ioStream *iostream.IOStream, httpClient connect.HTTPClient, opts ...connect.ClientOption) *App {
+	authenticated := append([]connect.ClientOption{AuthenticatedClientOption(cfg)}, opts...)
+
 	return &App{
 		Config:   cfg,
 		IOStream: ioStream,
 
-		AuthClient:    apiv1pbconnect.NewAuthServiceClient(http.DefaultClient, cfg.CurrentContext.URL),
-		UserClient:    apiv1pbconnect.NewUserServiceClient(http.DefaultClient, cfg.CurrentContext.URL, AuthenticatedClientOption(cfg)),
-		MachineClient: apiv1pbconnect.NewMachineServiceClient(http.DefaultClient, cfg.CurrentContext.URL, AuthenticatedClientOption(cfg)),
+		AuthClient:    apiv1pbconnect.NewAuthServiceClient(httpClient, cfg.CurrentContext.URL, opts...),
+		UserClient:    apiv1pbconnect.NewUserServiceClient(httpClient, cfg.CurrentContext.URL, authenticated...),
+		MachineClient: apiv1pbconnect.NewMachineServiceClient(httpClient, cfg.CurrentContext.URL, authenticated...),
+	}
+}
+
+// Close releases the resources held by the app once the command has run.
+func (a *App) Close() error {
+	if a.Trace == nil {
+		return nil
 	}
+	if err := a.Trace.Close(); err != nil {
+		return fmt.Errorf("closing trace: %w", err)
+	}
+	return nil
+}
+
+// WorkspaceID returns the workspace commands act on: the one given with
+// --workspace, or else the one of the current context.
+func (a *App) WorkspaceID() string {
+	return cmp.Or(a.Workspace, a.Config.CurrentContext.WorkspaceID)
 }
 
 // AuthenticatedClientOption returns a connect.ClientOption that automatically adds
-// the authentication header to all requests using the provided config.
+// the authentication header to all requests using the provided config. A token
+// from BAEPO_TOKEN takes precedence over the access token of auth login --web,
+// which takes precedence over the user ID and secret key.
 func AuthenticatedClientOption(cfg *config.Config) connect.ClientOption {
 	return connect.WithInterceptors(
 		connect.UnaryInterceptorFunc(
 			func(next connect.UnaryFunc) connect.UnaryFunc {
 				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-					if cfg.CurrentContext.UserID != "" && cfg.CurrentContext.SecretKey != "" {
+					if cfg.CurrentContext.Token != "" {
+						req.Header().Set("Authorization", fmt.Sprintf("Bearer %s", cfg.CurrentContext.Token))
+					} else if cfg.CurrentContext.AccessToken != "" {
+						req.Header().Set("Authorization", fmt.Sprintf("Bearer %s", cfg.CurrentContext.AccessToken))
+					} else if cfg.CurrentContext.UserID != "" && cfg.CurrentContext.SecretKey != "" {
 						token := base64.StdEncoding.EncodeToString(
 							[]byte(fmt.Sprintf("%s:%s", cfg.CurrentContext.UserID, cfg.CurrentContext.SecretKey)),
 						)
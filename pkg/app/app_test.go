@@ -0,0 +1,59 @@
+package app_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type userServer struct {
+	apiv1pbconnect.UnimplementedUserServiceHandler
+	authorization string
+}
+
+func (s *userServer) Me(_ context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[apiv1pb.UserMeResponse], error) {
+	s.authorization = req.Header().Get("Authorization")
+	return connect.NewResponse(&apiv1pb.UserMeResponse{User: &apiv1pb.User{Id: "u1"}}), nil
+}
+
+func TestAuthenticatedClientOption(t *testing.T) {
+	srv := &userServer{}
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewUserServiceHandler(srv))
+	httpSrv := httptest.NewServer(mux)
+	defer httpSrv.Close()
+
+	tests := []struct {
+		name     string
+		context  config.Context
+		expected string
+	}{
+		{"secret key", config.Context{UserID: "u1", SecretKey: "sk"}, "Bearer " + base64.StdEncoding.EncodeToString([]byte("u1:sk"))},
+		{"token", config.Context{Token: "tok-42"}, "Bearer tok-42"},
+		{"token over secret key", config.Context{UserID: "u1", SecretKey: "sk", Token: "tok-42"}, "Bearer tok-42"},
+		{"no user ID", config.Context{SecretKey: "sk"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{CurrentContext: &tt.context}
+			client := apiv1pbconnect.NewUserServiceClient(httpSrv.Client(), httpSrv.URL, app.AuthenticatedClientOption(cfg))
+
+			if _, err := client.Me(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if srv.authorization != tt.expected {
+				t.Errorf("Expected Authorization %q, got %q", tt.expected, srv.authorization)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const redacted = "[REDACTED]"
+
+// redactedHeaders are never written to a trace.
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields are message fields holding credentials. They are replaced in
+// traced requests and responses, whatever message they belong to.
+var redactedFields = []protoreflect.Name{"password", "secret_key", "token", "api_key"}
+
+// DebugClientOption returns a connect.ClientOption that writes a trace of every
+// call to w: procedure, headers, request and response messages as JSON,
+// latency and errors. Credentials are redacted.
+func DebugClientOption(w io.Writer) connect.ClientOption {
+	t := &tracer{w: w}
+	return connect.WithInterceptors(
+		connect.UnaryInterceptorFunc(
+			func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					start := time.Now()
+					res, err := next(ctx, req)
+					t.trace(req, res, err, start, time.Since(start))
+					return res, err
+				}
+			},
+		),
+	)
+}
+
+type tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// trace writes a call in a single write, so that concurrent calls do not
+// interleave.
+func (t *tracer) trace(req connect.AnyRequest, res connect.AnyResponse, err error, start time.Time, latency time.Duration) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "%s --> %s %s\n", start.UTC().Format(time.RFC3339Nano), req.HTTPMethod(), req.Spec().Procedure)
+	writeHeaders(&b, req.Header())
+	writeMessage(&b, req.Any())
+
+	end := start.Add(latency).UTC().Format(time.RFC3339Nano)
+	var connectErr *connect.Error
+	switch {
+	case errors.As(err, &connectErr):
+		fmt.Fprintf(&b, "%s <-- %s %s error: %v\n", end, req.Spec().Procedure, latency.Round(time.Millisecond), connectErr)
+		writeHeaders(&b, connectErr.Meta())
+		for _, detail := range connectErr.Details() {
+			msg, detailErr := detail.Value()
+			if detailErr != nil {
+				fmt.Fprintf(&b, "  detail %s: %v\n", detail.Type(), detailErr)
+				continue
+			}
+			fmt.Fprintf(&b, "  detail %s:\n", detail.Type())
+			writeMessage(&b, msg)
+		}
+	case err != nil:
+		fmt.Fprintf(&b, "%s <-- %s %s error: %v\n", end, req.Spec().Procedure, latency.Round(time.Millisecond), err)
+	default:
+		fmt.Fprintf(&b, "%s <-- %s %s\n", end, req.Spec().Procedure, latency.Round(time.Millisecond))
+		writeHeaders(&b, res.Header())
+		writeMessage(&b, res.Any())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Write(b.Bytes())
+}
+
+func writeHeaders(b *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		value := strings.Join(header.Values(k), ", ")
+		if slices.ContainsFunc(redactedHeaders, func(h string) bool { return strings.EqualFold(h, k) }) {
+			value = redacted
+		}
+		fmt.Fprintf(b, "  %s: %s\n", k, value)
+	}
+}
+
+func writeMessage(b *bytes.Buffer, msg any) {
+	m, ok := msg.(proto.Message)
+	if !ok || m == nil {
+		return
+	}
+
+	out, err := protojson.Marshal(redactMessage(m))
+	if err != nil {
+		fmt.Fprintf(b, "  <unable to encode %T: %v>\n", msg, err)
+		return
+	}
+	fmt.Fprintf(b, "  %s\n", out)
+}
+
+// redactMessage returns a copy of msg whose credential fields are replaced.
+func redactMessage(msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	redactFields(msg.ProtoReflect())
+	return msg
+}
+
+func redactFields(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case slices.Contains(redactedFields, fd.Name()) && fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap():
+			m.Set(fd, protoreflect.ValueOfString(redacted))
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				redactFields(v.List().Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			redactFields(v.Message())
+		}
+		return true
+	})
+}
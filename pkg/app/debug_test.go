@@ -0,0 +1,63 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+)
+
+type authServer struct {
+	apiv1pbconnect.UnimplementedAuthServiceHandler
+}
+
+func (authServer) Login(_ context.Context, req *connect.Request[apiv1pb.AuthLoginRequest]) (*connect.Response[apiv1pb.AuthLoginResponse], error) {
+	if req.Msg.Email != "lou@corp.com" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	return connect.NewResponse(&apiv1pb.AuthLoginResponse{UserId: "u1", SecretKey: "sk-live-42"}), nil
+}
+
+func TestDebugClientOption(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewAuthServiceHandler(authServer{}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var trace bytes.Buffer
+	client := apiv1pbconnect.NewAuthServiceClient(srv.Client(), srv.URL, app.DebugClientOption(&trace))
+
+	req := connect.NewRequest(&apiv1pb.AuthLoginRequest{Email: "lou@corp.com", Password: "hunter2"})
+	req.Header().Set("Authorization", "Bearer abc")
+	if _, err := client.Login(context.Background(), req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := trace.String()
+	for _, secret := range []string{"hunter2", "sk-live-42", "Bearer abc"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected %q to be redacted from the trace:\n%s", secret, out)
+		}
+	}
+	for _, expected := range []string{"--> POST " + apiv1pbconnect.AuthServiceLoginProcedure, "lou@corp.com", `"userId":"u1"`} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected the trace to contain %q:\n%s", expected, out)
+		}
+	}
+
+	trace.Reset()
+	_, err := client.Login(context.Background(), connect.NewRequest(&apiv1pb.AuthLoginRequest{Email: "nobody@corp.com"}))
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !strings.Contains(trace.String(), "error: unauthenticated") {
+		t.Errorf("Expected the trace to contain the error:\n%s", trace.String())
+	}
+}
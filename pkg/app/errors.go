@@ -0,0 +1,63 @@
+package app
+
+import (
+	"errors"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/errdetails"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+// APIError reports a failed API call, prefixed by what was being done, and
+// returns the error the command should exit with. Errors that do not come from
+// the API, or whose code has no specific exit code, map to fallback.
+//
+// The details attached by the API are reported as well: violations are listed
+// under the error, and the request ID is given for support.
+func (a *App) APIError(err error, fallback error, action string) error {
+	opts := iostream.ErrorOptions{Error: "%s: %v"}
+	msg := any(err)
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		opts.Code = connectErr.Code().String()
+		msg = connectErr.Message()
+		if connectErr.Message() == "" {
+			msg = opts.Code
+		}
+
+		details := errdetails.FromError(err)
+		if details.Message != "" {
+			msg = details.Message
+		}
+		for _, v := range details.Violations {
+			opts.Violations = append(opts.Violations, iostream.ErrorViolation{
+				Kind:        v.Kind,
+				Subject:     v.Subject,
+				Description: v.Description,
+			})
+		}
+		opts.Details = detailsToHumanString(details)
+		opts.RequestID = details.RequestID
+	}
+
+	a.IOStream.ErrorWithDetails(opts, action, msg)
+	return baepoerrors.FromAPI(err, fallback)
+}
+
+func detailsToHumanString(d *errdetails.Details) string {
+	var parts []string
+	if d.Reason != "" {
+		reason := d.Reason
+		if d.Domain != "" {
+			reason = d.Domain + "/" + d.Reason
+		}
+		parts = append(parts, "reason: "+reason)
+	}
+	if d.RetryDelay > 0 {
+		parts = append(parts, "retry in "+d.RetryDelay.String())
+	}
+	return strings.Join(parts, ", ")
+}
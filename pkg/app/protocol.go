@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+)
+
+const (
+	ProtocolConnect = "connect"
+	ProtocolGRPC    = "grpc"
+	ProtocolGRPCWeb = "grpcweb"
+
+	EncodingProto = "proto"
+	EncodingJSON  = "json"
+
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
+// ProtocolClientOptions returns the connect.ClientOption selecting the
+// protocol, encoding and compression of a context. Empty settings keep the
+// connect defaults: Connect protocol, binary protobuf and gzip responses.
+func ProtocolClientOptions(c *config.Context) ([]connect.ClientOption, error) {
+	var opts []connect.ClientOption
+
+	switch c.Protocol {
+	case "", ProtocolConnect:
+	case ProtocolGRPC:
+		opts = append(opts, connect.WithGRPC())
+	case ProtocolGRPCWeb:
+		opts = append(opts, connect.WithGRPCWeb())
+	default:
+		return nil, fmt.Errorf("unknown protocol %q, expected %s, %s or %s", c.Protocol, ProtocolConnect, ProtocolGRPC, ProtocolGRPCWeb)
+	}
+
+	switch c.Encoding {
+	case "", EncodingProto:
+	case EncodingJSON:
+		opts = append(opts, connect.WithProtoJSON())
+	default:
+		return nil, fmt.Errorf("unknown encoding %q, expected %s or %s", c.Encoding, EncodingProto, EncodingJSON)
+	}
+
+	switch c.Compression {
+	case "":
+	case CompressionGzip:
+		opts = append(opts, connect.WithSendGzip())
+	case CompressionNone:
+		// Unregistering gzip also stops asking the server for compressed
+		// responses.
+		opts = append(opts, connect.WithAcceptCompression(CompressionGzip, nil, nil))
+	default:
+		return nil, fmt.Errorf("unknown compression %q, expected %s or %s", c.Compression, CompressionGzip, CompressionNone)
+	}
+
+	return opts, nil
+}
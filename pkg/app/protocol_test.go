@@ -0,0 +1,92 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+)
+
+func TestProtocolClientOptions(t *testing.T) {
+	var mu sync.Mutex
+	var seen *http.Request
+
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewAuthServiceHandler(authServer{}))
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = r
+		mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	// Serve h2c as well, for plaintext gRPC.
+	srv.Config.Protocols = &http.Protocols{}
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	tests := []struct {
+		context     config.Context
+		contentType string
+		proto       int
+		encoding    string
+	}{
+		{config.Context{}, "application/proto", 1, ""},
+		{config.Context{Encoding: app.EncodingJSON, Compression: app.CompressionGzip}, "application/json", 1, "gzip"},
+		{config.Context{Protocol: app.ProtocolGRPC}, "application/grpc", 2, ""},
+		{config.Context{Protocol: app.ProtocolGRPC, Encoding: app.EncodingJSON}, "application/grpc+json", 2, ""},
+		{config.Context{Protocol: app.ProtocolGRPCWeb, Compression: app.CompressionNone}, "application/grpc-web+proto", 1, ""},
+	}
+
+	for _, test := range tests {
+		c := test.context
+		c.URL = srv.URL
+
+		httpClient, err := app.NewHTTPClient(&c)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		opts, err := app.ProtocolClientOptions(&c)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		client := apiv1pbconnect.NewAuthServiceClient(httpClient, c.URL, opts...)
+		res, err := client.Login(context.Background(), connect.NewRequest(&apiv1pb.AuthLoginRequest{Email: "lou@corp.com"}))
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", test.context, err)
+			continue
+		}
+		if res.Msg.UserId != "u1" {
+			t.Errorf("%+v: unexpected response %v", test.context, res.Msg)
+		}
+
+		mu.Lock()
+		if ct := seen.Header.Get("Content-Type"); ct != test.contentType {
+			t.Errorf("%+v: expected content type %s, got %s", test.context, test.contentType, ct)
+		}
+		if seen.ProtoMajor != test.proto {
+			t.Errorf("%+v: expected HTTP/%d, got %s", test.context, test.proto, seen.Proto)
+		}
+		if ce := seen.Header.Get("Content-Encoding"); ce != test.encoding {
+			t.Errorf("%+v: expected content encoding %q, got %q", test.context, test.encoding, ce)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestProtocolClientOptionsInvalid(t *testing.T) {
+	for _, c := range []config.Context{{Protocol: "http3"}, {Encoding: "xml"}, {Compression: "brotli"}} {
+		if _, err := app.ProtocolClientOptions(&c); err == nil {
+			t.Errorf("%+v: expected an error", c)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/errdetails"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+)
+
+// DefaultTimeout is the timeout of a single call when neither --timeout nor
+// the current context set one.
+const DefaultTimeout = 30 * time.Second
+
+// idempotentProcedures are also retried when they timed out or were aborted,
+// as calling them twice has no effect.
+var idempotentProcedures = []string{
+	apiv1pbconnect.MachineServiceListProcedure,
+	apiv1pbconnect.MachineServiceFindByIdProcedure,
+	apiv1pbconnect.UserServiceMeProcedure,
+}
+
+// RetryPolicy configures how failed calls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made before giving up, including the
+	// first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled on every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts, including server hints.
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// RetryClientOption returns a connect.ClientOption retrying calls that failed
+// with a transient error, waiting with a jittered exponential backoff or for
+// the delay requested by the server.
+//
+// Idempotent calls are retried when the server is unavailable, rate limiting,
+// or when they timed out or were aborted. Other calls, such as creating a
+// machine, are only retried when the request is known not to have been
+// processed: when the connection to the server could not be established, or
+// when the server asked to retry later.
+func RetryClientOption(policy RetryPolicy) connect.ClientOption {
+	return connect.WithInterceptors(
+		connect.UnaryInterceptorFunc(
+			func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					for attempt := 1; ; attempt++ {
+						res, err := next(ctx, req)
+						if err == nil || attempt >= policy.MaxAttempts || !retryable(req, err) {
+							return res, err
+						}
+
+						timer := time.NewTimer(policy.delay(attempt, err))
+						select {
+						case <-ctx.Done():
+							timer.Stop()
+							return nil, err
+						case <-timer.C:
+						}
+					}
+				}
+			},
+		),
+	)
+}
+
+// TimeoutClientOption returns a connect.ClientOption bounding each call, and
+// each of its retries, to timeout. A zero timeout disables it.
+func TimeoutClientOption(timeout time.Duration) connect.ClientOption {
+	return connect.WithInterceptors(
+		connect.UnaryInterceptorFunc(
+			func(next connect.UnaryFunc) connect.UnaryFunc {
+				return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					if timeout <= 0 {
+						return next(ctx, req)
+					}
+					ctx, cancel := context.WithTimeout(ctx, timeout)
+					defer cancel()
+					return next(ctx, req)
+				}
+			},
+		),
+	)
+}
+
+func retryable(req connect.AnyRequest, err error) bool {
+	idempotent := req.Spec().IdempotencyLevel != connect.IdempotencyUnknown ||
+		slices.Contains(idempotentProcedures, req.Spec().Procedure)
+
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable:
+		// Unavailable is also reported when the connection dropped after
+		// the request was sent, retrying could then apply it twice.
+		return idempotent || dialFailed(err)
+	case connect.CodeResourceExhausted:
+		if idempotent {
+			return true
+		}
+		_, ok := retryHint(err)
+		return ok
+	case connect.CodeDeadlineExceeded, connect.CodeAborted:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// dialFailed reports whether err happened while connecting to the server,
+// before the request was sent.
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// delay returns how long to wait before the retry following attempt. A hint
+// given by the server wins over the backoff.
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	if hint, ok := retryHint(err); ok {
+		return min(hint, p.MaxDelay)
+	}
+
+	backoff := min(p.BaseDelay<<(attempt-1), p.MaxDelay)
+	// Wait anywhere between half and the whole backoff, so that clients
+	// failing together do not retry together.
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// retryHint extracts the delay requested by the server, either from a
+// RetryInfo error detail or from a Retry-After header.
+func retryHint(err error) (time.Duration, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0, false
+	}
+
+	if details := errdetails.FromError(err); details.RetryDelay > 0 {
+		return details.RetryDelay, true
+	}
+
+	value := connectErr.Meta().Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
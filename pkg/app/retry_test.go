@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    20 * time.Millisecond,
+}
+
+// flakyMachineServer fails the first failures calls of each procedure with
+// code, and hangs on FindById for machine "slow".
+type flakyMachineServer struct {
+	apiv1pbconnect.UnimplementedMachineServiceHandler
+
+	failures   int32
+	code       connect.Code
+	retryAfter string
+	// abort drops the connection of Create calls after reading the request.
+	abort bool
+	calls atomic.Int32
+}
+
+func (s *flakyMachineServer) fail() error {
+	if s.calls.Add(1) > s.failures {
+		return nil
+	}
+	err := connect.NewError(s.code, errors.New("try again"))
+	if s.retryAfter != "" {
+		err.Meta().Set("Retry-After", s.retryAfter)
+	}
+	return err
+}
+
+func (s *flakyMachineServer) FindById(ctx context.Context, req *connect.Request[apiv1pb.MachineFindByIdRequest]) (*connect.Response[apiv1pb.MachineFindByIdResponse], error) {
+	if req.Msg.MachineId == "slow" {
+		s.calls.Add(1)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&apiv1pb.MachineFindByIdResponse{Machine: &apiv1pb.Machine{Id: req.Msg.MachineId}}), nil
+}
+
+func (s *flakyMachineServer) Create(_ context.Context, _ *connect.Request[apiv1pb.MachineCreateRequest]) (*connect.Response[apiv1pb.MachineCreateResponse], error) {
+	if s.abort {
+		s.calls.Add(1)
+		panic(http.ErrAbortHandler)
+	}
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&apiv1pb.MachineCreateResponse{Machine: &apiv1pb.Machine{Id: "m1"}}), nil
+}
+
+func newFlakyClient(t *testing.T, s *flakyMachineServer, timeout time.Duration) apiv1pbconnect.MachineServiceClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewMachineServiceHandler(s))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return apiv1pbconnect.NewMachineServiceClient(srv.Client(), srv.URL,
+		RetryClientOption(testRetryPolicy),
+		TimeoutClientOption(timeout),
+	)
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	s := &flakyMachineServer{failures: 2, code: connect.CodeUnavailable}
+	client := newFlakyClient(t, s, time.Second)
+
+	if _, err := client.FindById(context.Background(), connect.NewRequest(&apiv1pb.MachineFindByIdRequest{MachineId: "m1"})); err != nil {
+		t.Fatalf("Expected the call to succeed after retries, got %v", err)
+	}
+	if calls := s.calls.Load(); calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	s := &flakyMachineServer{failures: 10, code: connect.CodeResourceExhausted, retryAfter: "3600"}
+	client := newFlakyClient(t, s, time.Second)
+
+	start := time.Now()
+	_, err := client.Create(context.Background(), connect.NewRequest(&apiv1pb.MachineCreateRequest{}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("Expected a resource exhausted error, got %v", err)
+	}
+	if calls := s.calls.Load(); calls != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("Expected %d calls, got %d", testRetryPolicy.MaxAttempts, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the Retry-After hint to be capped by MaxDelay, took %s", elapsed)
+	}
+}
+
+func TestNoRetryOfReceivedCreate(t *testing.T) {
+	for name, s := range map[string]*flakyMachineServer{
+		"unavailable":        {failures: 1, code: connect.CodeUnavailable},
+		"dropped connection": {abort: true},
+		"rate limited":       {failures: 1, code: connect.CodeResourceExhausted},
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := newFlakyClient(t, s, time.Second)
+
+			if _, err := client.Create(context.Background(), connect.NewRequest(&apiv1pb.MachineCreateRequest{})); err == nil {
+				t.Fatal("Expected an error")
+			}
+			if calls := s.calls.Load(); calls != 1 {
+				t.Errorf("Expected Create not to be retried once received, got %d calls", calls)
+			}
+		})
+	}
+}
+
+func TestRetryCreateDialErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	var attempts atomic.Int32
+	count := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			attempts.Add(1)
+			return next(ctx, req)
+		}
+	})
+	client := apiv1pbconnect.NewMachineServiceClient(http.DefaultClient, url,
+		RetryClientOption(testRetryPolicy),
+		connect.WithInterceptors(count),
+	)
+
+	_, err := client.Create(context.Background(), connect.NewRequest(&apiv1pb.MachineCreateRequest{}))
+	if connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("Expected an unavailable error, got %v", err)
+	}
+	if n := attempts.Load(); n != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("Expected Create to be retried when the server cannot be reached, got %d attempts", n)
+	}
+}
+
+func TestNoRetryOnPermanentErrors(t *testing.T) {
+	s := &flakyMachineServer{failures: 1, code: connect.CodeInvalidArgument}
+	client := newFlakyClient(t, s, time.Second)
+
+	if _, err := client.FindById(context.Background(), connect.NewRequest(&apiv1pb.MachineFindByIdRequest{MachineId: "m1"})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+	if calls := s.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := &flakyMachineServer{}
+	client := newFlakyClient(t, s, 50*time.Millisecond)
+
+	_, err := client.FindById(context.Background(), connect.NewRequest(&apiv1pb.MachineFindByIdRequest{MachineId: "slow"}))
+	if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+		t.Fatalf("Expected a deadline exceeded error, got %v", err)
+	}
+	// FindById is idempotent, so it is retried after timing out.
+	if calls := s.calls.Load(); calls != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("Expected %d calls, got %d", testRetryPolicy.MaxAttempts, calls)
+	}
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+)
+
+// NewHTTPClient builds the HTTP client used to reach the API of a context,
+// from its TLS, proxy and header settings.
+//
+// HTTP/2 is negotiated over TLS. gRPC requires HTTP/2, so plaintext gRPC
+// contexts speak HTTP/2 without TLS (h2c) directly.
+func NewHTTPClient(c *config.Context) (*http.Client, error) {
+	transport, err := newTransport(c)
+	if err != nil {
+		return nil, err
+	}
+	transport.ForceAttemptHTTP2 = true
+
+	if c.Protocol == ProtocolGRPC && strings.HasPrefix(c.URL, "http://") {
+		var protocols http.Protocols
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = &protocols
+	}
+
+	var rt http.RoundTripper = transport
+	if len(c.Headers) > 0 {
+		rt = &headerTransport{next: transport, headers: c.Headers}
+	}
+	return &http.Client{Transport: rt}, nil
+}
+
+// NewOAuthHTTPClient builds the HTTP client used to reach the authorization
+// server of a context. It shares the TLS and proxy settings of the API client,
+// but not the custom headers, which are meant for the API only.
+func NewOAuthHTTPClient(c *config.Context) (*http.Client, error) {
+	transport, err := newTransport(c)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// FailingHTTPClient returns a client failing every request with err, the error
+// that prevented building the client of a context.
+func FailingHTTPClient(err error) connect.HTTPClient {
+	return failingHTTPClient{err: err}
+}
+
+type failingHTTPClient struct {
+	err error
+}
+
+func (c failingHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return nil, c.err
+}
+
+// newTransport returns a transport with the TLS and proxy settings of c.
+func newTransport(c *config.Context) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := newTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if c.Proxy != "" {
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		if !slices.Contains([]string{"http", "https", "socks5"}, proxyURL.Scheme) || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q, expected an http://, https:// or socks5:// URL", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return transport, nil
+}
+
+func newTLSConfig(c *config.Context) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+
+		// The CA is trusted on top of the system ones, so that a context
+		// can mix private and public endpoints (e.g. through a proxy).
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificate found in CA file %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("client_cert and client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// headerTransport adds custom headers to every request, without replacing the
+// ones set by the CLI such as Authorization or Content-Type.
+type headerTransport struct {
+	next    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,187 @@
+package app_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+)
+
+// writePEM writes a PEM block to a file of dir and returns its path.
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newClientCert creates a CA and a client certificate signed by it, and
+// returns the CA along with the paths of the client key pair.
+func newClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test client CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "cli"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ca, writePEM(t, dir, "client.pem", "CERTIFICATE", der), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func get(t *testing.T, c *config.Context, url string) (*http.Response, error) {
+	t.Helper()
+	client, err := app.NewHTTPClient(c)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res, err := client.Get(url)
+	if err == nil {
+		res.Body.Close()
+	}
+	return res, err
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCA, clientCert, clientKey := newClientCert(t, dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "corp" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	headers := map[string]string{"X-Tenant": "corp"}
+
+	if _, err := get(t, &config.Context{ClientCert: clientCert, ClientKey: clientKey}, srv.URL); err == nil {
+		t.Error("Expected the server certificate to be rejected without ca_file")
+	}
+	if _, err := get(t, &config.Context{CAFile: caFile}, srv.URL); err == nil {
+		t.Error("Expected the server to reject a client without certificate")
+	}
+
+	res, err := get(t, &config.Context{CAFile: caFile, ClientCert: clientCert, ClientKey: clientKey, Headers: headers}, srv.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error with ca_file and client certificate: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected the custom header to be sent, got status %d", res.StatusCode)
+	}
+
+	if _, err := get(t, &config.Context{InsecureSkipVerify: true, ClientCert: clientCert, ClientKey: clientKey}, srv.URL); err != nil {
+		t.Errorf("Unexpected error with insecure_skip_verify: %v", err)
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.baepo.invalid" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer proxy.Close()
+
+	res, err := get(t, &config.Context{Proxy: proxy.URL}, "http://api.baepo.invalid/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.StatusCode != http.StatusTeapot {
+		t.Errorf("Expected the request to go through the proxy, got status %d", res.StatusCode)
+	}
+}
+
+func TestOAuthHTTPClientHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Gateway-Key") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := app.NewOAuthHTTPClient(&config.Context{Headers: map[string]string{"X-Gateway-Key": "gw-secret"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Error("Expected the custom headers of the context not to be sent to the authorization server")
+	}
+}
+
+func TestHTTPClientInvalidSettings(t *testing.T) {
+	for name, c := range map[string]*config.Context{
+		"missing CA file":    {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"client cert alone":  {ClientCert: "client.pem"},
+		"proxy without host": {Proxy: "http://"},
+		"unsupported scheme": {Proxy: "ftp://proxy:21"},
+	} {
+		if _, err := app.NewHTTPClient(c); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -3,35 +3,119 @@ package baepocmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
 
+	"github.com/baepo-cloud/baepo-cli/pkg/alias"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/aliascmd"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/plugincmd"
 	"github.com/baepo-cloud/baepo-cli/pkg/cmd/root"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
 )
 
 type ExitCode int
 
+// Exit codes are documented in the help of the root command, keep both in
+// sync.
 const (
-	exitOK      ExitCode = 0
-	exitError   ExitCode = 1
-	exitCancel  ExitCode = 2
-	exitAuth    ExitCode = 4
-	exitPending ExitCode = 8
+	exitOK          ExitCode = 0
+	exitError       ExitCode = 1
+	exitCancel      ExitCode = 2
+	exitInvalidArgs ExitCode = 3
+	exitAuth        ExitCode = 4
+	exitPermission  ExitCode = 5
+	exitNotFound    ExitCode = 6
+	exitUnavailable ExitCode = 7
+	exitPending     ExitCode = 8
+	exitTimeout     ExitCode = 9
+	exitPartial     ExitCode = 16
 )
 
 func Main() ExitCode {
-
-	ctx, ctxCancel := context.WithCancel(context.Background())
+	ctx, ctxCancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer ctxCancel()
+	go func() {
+		// The first interrupt cancels ctx, restore the default handler so
+		// that a second one kills commands blocked outside of ctx.
+		<-ctx.Done()
+		ctxCancel()
+	}()
 
 	cmdRoot := root.NewCmdRoot()
-	if _, err := cmdRoot.ExecuteContextC(ctx); err != nil {
-		switch {
-		case errors.Is(err, baepoerrors.AuthError):
-			return exitAuth
-		default:
+	args := os.Args[1:]
+
+	// Aliases and plugins are resolved before cobra, which would reject them
+	// as unknown commands, after the global flags given before them. Config
+	// errors are reported by the command run.
+	globals, rest := root.SplitGlobalFlags(cmdRoot, args)
+	cfg, cfgErr := config.LoadConfig(globals.Context)
+	if expansion, ok := aliascmd.Lookup(cmdRoot, cfg, rest); ok {
+		if alias.IsShell(expansion) {
+			return runShellAlias(cfg, globals, rest[0], expansion, rest[1:])
+		}
+
+		expanded, err := alias.Expand(expansion, rest[1:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: alias %s: %v\n", rest[0], err)
+			return exitInvalidArgs
+		}
+		args = append(slices.Clone(globals.Args), expanded...)
+		rest = expanded
+	}
+
+	if p, pluginArgs, ok := plugincmd.Lookup(cmdRoot, rest); ok {
+		if cfgErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", cfgErr)
 			return exitError
 		}
+		return runPlugin(cfg, globals, p, pluginArgs)
+	}
+
+	cmdRoot.SetArgs(args)
+	cmd, err := cmdRoot.ExecuteContextC(ctx)
+	// The app is closed here rather than in a post run hook, which cobra
+	// skips when the command fails.
+	if a := app.FromContext(cmd.Context()); a != nil {
+		if err := a.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if err != nil {
+		if !baepoerrors.Reported(err) {
+			// Usage errors, such as unknown flags or commands, are returned
+			// by cobra before any command runs.
+			fmt.Fprintf(os.Stderr, "Error: %v\nRun '%s --help' for usage.\n", err, cmd.CommandPath())
+			return exitInvalidArgs
+		}
+		return exitCode(err)
 	}
 
 	return exitOK
 }
+
+func exitCode(err error) ExitCode {
+	switch {
+	case errors.Is(err, baepoerrors.AuthError):
+		return exitAuth
+	case errors.Is(err, baepoerrors.CancelError):
+		return exitCancel
+	case errors.Is(err, baepoerrors.PartialError):
+		return exitPartial
+	case errors.Is(err, baepoerrors.InvalidArgsError):
+		return exitInvalidArgs
+	case errors.Is(err, baepoerrors.PermissionError):
+		return exitPermission
+	case errors.Is(err, baepoerrors.NotFoundError):
+		return exitNotFound
+	case errors.Is(err, baepoerrors.UnavailableError):
+		return exitUnavailable
+	case errors.Is(err, baepoerrors.TimeoutError):
+		return exitTimeout
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,55 @@
+package baepocmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/alias"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/root"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+)
+
+// runPlugin runs p with the resolved context in its environment.
+func runPlugin(cfg *config.Config, globals *root.GlobalFlags, p *plugin.Plugin, args []string) ExitCode {
+	return runExternal(plugin.Command(p, args, externalEnv(cfg, globals)), "plugin "+p.Path)
+}
+
+// runShellAlias runs a shell alias with the same environment as plugins.
+func runShellAlias(cfg *config.Config, globals *root.GlobalFlags, name, expansion string, args []string) ExitCode {
+	return runExternal(alias.ShellCommand(expansion, args, externalEnv(cfg, globals)), "alias "+name)
+}
+
+// externalEnv returns the environment of plugins and shell aliases, with the
+// global flags given before their name applied: --workspace overrides BAEPO_WORKSPACE_ID and
+// --json sets BAEPO_JSON=1.
+func externalEnv(cfg *config.Config, globals *root.GlobalFlags) []string {
+	if cfg != nil && globals.Workspace != "" {
+		cfg.CurrentContext.WorkspaceID = globals.Workspace
+	}
+	env := plugin.Env(cfg)
+	if globals.JSON {
+		env = append(env, "BAEPO_JSON=1")
+	}
+	return env
+}
+
+// runExternal runs cmd and returns its exit code.
+func runExternal(cmd *exec.Cmd, what string) ExitCode {
+	// Interrupts reach the command too, as it shares the terminal. The first
+	// one is ignored here so that the command can handle it.
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		return ExitCode(exitErr.ExitCode())
+	default:
+		fmt.Fprintf(os.Stderr, "Error: running %s: %v\n", what, err)
+		return exitError
+	}
+}
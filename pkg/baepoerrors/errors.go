@@ -1,10 +1,64 @@
 package baepoerrors
 
-import "errors"
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+)
 
 var (
 	AuthError        = errors.New("authentication error")
 	ConfigError      = errors.New("configuration error")
 	MachineError     = errors.New("machine error")
 	InvalidArgsError = errors.New("invalid arguments")
+	PartialError     = errors.New("partial failure")
+	CancelError      = errors.New("cancelled")
+	NotFoundError    = errors.New("not found")
+	PermissionError  = errors.New("permission denied")
+	UnavailableError = errors.New("service unavailable")
+	TimeoutError     = errors.New("timeout")
 )
+
+var all = []error{
+	AuthError, ConfigError, MachineError, InvalidArgsError, PartialError, CancelError,
+	NotFoundError, PermissionError, UnavailableError, TimeoutError,
+}
+
+// Reported tells whether err is one of the errors above. Commands return them
+// after printing what went wrong, any other error has not been printed yet.
+func Reported(err error) bool {
+	for _, e := range all {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromAPI maps the error of an API call to the error matching its connect
+// code, or to fallback when the code has no specific meaning for the CLI.
+func FromAPI(err error, fallback error) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return fallback
+	}
+
+	switch connectErr.Code() {
+	case connect.CodeNotFound:
+		return NotFoundError
+	case connect.CodePermissionDenied:
+		return PermissionError
+	case connect.CodeUnauthenticated:
+		return AuthError
+	case connect.CodeInvalidArgument, connect.CodeFailedPrecondition, connect.CodeOutOfRange, connect.CodeAlreadyExists:
+		return InvalidArgsError
+	case connect.CodeUnavailable, connect.CodeResourceExhausted:
+		return UnavailableError
+	case connect.CodeDeadlineExceeded:
+		return TimeoutError
+	case connect.CodeCanceled:
+		return CancelError
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,34 @@
+package baepoerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+)
+
+func TestFromAPI(t *testing.T) {
+	tests := map[connect.Code]error{
+		connect.CodeNotFound:         baepoerrors.NotFoundError,
+		connect.CodePermissionDenied: baepoerrors.PermissionError,
+		connect.CodeUnauthenticated:  baepoerrors.AuthError,
+		connect.CodeInvalidArgument:  baepoerrors.InvalidArgsError,
+		connect.CodeUnavailable:      baepoerrors.UnavailableError,
+		connect.CodeDeadlineExceeded: baepoerrors.TimeoutError,
+		connect.CodeInternal:         baepoerrors.MachineError,
+	}
+
+	for code, expected := range tests {
+		// Errors are usually wrapped on their way up.
+		err := fmt.Errorf("calling API: %w", connect.NewError(code, errors.New("boom")))
+		if got := baepoerrors.FromAPI(err, baepoerrors.MachineError); got != expected {
+			t.Errorf("FromAPI(%s) = %v, expected %v", code, got, expected)
+		}
+	}
+
+	if got := baepoerrors.FromAPI(errors.New("not an API error"), baepoerrors.ConfigError); got != baepoerrors.ConfigError {
+		t.Errorf("Expected the fallback for a non API error, got %v", got)
+	}
+}
@@ -0,0 +1,50 @@
+package aliascmd
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/spf13/cobra"
+)
+
+func newDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "delete <name>",
+		Aliases:     []string{"rm"},
+		Short:       "Delete an alias",
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			name := args[0]
+			expansion, exists := a.Config.Aliases[name]
+			if !exists {
+				a.IOStream.Error("Alias '%s' does not exist.", name)
+				return baepoerrors.InvalidArgsError
+			}
+
+			delete(a.Config.Aliases, name)
+
+			if a.DryRun {
+				a.IOStream.Object(&helper.AliasFmt{Name: name, Expansion: expansion}, helper.AliasFmtMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, the alias was not deleted.")
+				return nil
+			}
+
+			if err := config.SaveConfig(a.Config); err != nil {
+				a.IOStream.Error("Failed to save config: %v", err)
+				return baepoerrors.ConfigError
+			}
+
+			a.IOStream.Message("Deleted alias '%s'", name)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
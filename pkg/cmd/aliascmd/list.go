@@ -0,0 +1,42 @@
+package aliascmd
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List aliases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			var list []*helper.AliasFmt
+			for _, name := range slices.Sorted(maps.Keys(a.Config.Aliases)) {
+				list = append(list, &helper.AliasFmt{
+					Name:      name,
+					Expansion: a.Config.Aliases[name],
+				})
+			}
+
+			if len(list) == 0 {
+				a.IOStream.Message("No aliases found.")
+				return nil
+			}
+
+			a.IOStream.Array(list, helper.AliasFmtMapping(), iostream.ObjectOptions{})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+package aliascmd
+
+import (
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/plugincmd"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func NewAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage command aliases",
+		Long: `Aliases are shortcuts for baepo commands, stored in the aliases section of
+the config file.
+
+In an alias, $1, $2... are replaced by the arguments it is called with and $@
+by all of them. Arguments that are not referenced are appended.
+
+Aliases starting with ! are run by sh, and can use pipes and other commands.
+Their arguments are $1, $2... and $@, and the resolved context is exported as
+for plugins.
+
+Aliases cannot shadow built-in commands, which always take precedence.`,
+	}
+
+	cmd.AddCommand(newSetCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newDeleteCmd())
+
+	return cmd
+}
+
+// Lookup returns the expansion of the alias named by the first element of
+// args, unless it is a built-in command of root.
+func Lookup(root *cobra.Command, cfg *config.Config, args []string) (string, bool) {
+	if cfg == nil || len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", false
+	}
+	expansion, ok := cfg.Aliases[args[0]]
+	if !ok || plugincmd.IsBuiltin(root, args[0]) {
+		return "", false
+	}
+	return expansion, true
+}
@@ -0,0 +1,117 @@
+package aliascmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/alias"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/plugincmd"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+	"github.com/spf13/cobra"
+)
+
+func newSetCmd() *cobra.Command {
+	var shell bool
+
+	cmd := &cobra.Command{
+		Use:   "set <name> <expansion>",
+		Short: "Create or change an alias",
+		Long: `Create or change an alias.
+
+Global flags given before the name of an alias apply to it: baepo -x prod ml
+runs baepo -x prod machine list.
+
+Shell aliases get the context selected with --context and the workspace given
+with --workspace in their environment, as plugins do, but the other global
+flags are not passed to the commands they run. --json is only exported as
+BAEPO_JSON=1, add --json to the commands of the alias where needed. Flags given
+after the name of a shell alias are arguments of the alias, in $@.`,
+		Example: `# baepo ml runs baepo machine list
+baepo alias set ml 'machine list'
+
+# baepo mi ID runs baepo machine inspect ID
+baepo alias set mi 'machine inspect $1'
+
+# Shell aliases can pipe commands
+baepo alias set --shell ids 'baepo machine list --json | jq -r ".[].id"'`,
+		Args:        cobra.ExactArgs(2),
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			name, expansion := args[0], args[1]
+			if shell && !alias.IsShell(expansion) {
+				expansion = alias.ShellPrefix + expansion
+			}
+
+			if err := validateAlias(cmd.Root(), name, expansion); err != nil {
+				a.IOStream.Error("Invalid alias: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+
+			_, exists := a.Config.Aliases[name]
+			if a.Config.Aliases == nil {
+				a.Config.Aliases = map[string]string{}
+			}
+			a.Config.Aliases[name] = expansion
+
+			if a.DryRun {
+				a.IOStream.Object(&helper.AliasFmt{Name: name, Expansion: expansion}, helper.AliasFmtMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, the config was not saved.")
+				return nil
+			}
+
+			if err := config.SaveConfig(a.Config); err != nil {
+				a.IOStream.Error("Failed to save config: %v", err)
+				return baepoerrors.ConfigError
+			}
+
+			if exists {
+				a.IOStream.Message("Changed alias '%s'", name)
+			} else {
+				a.IOStream.Message("Added alias '%s'", name)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&shell, "shell", "s", false, "Run the expansion with sh, same as prefixing it with !")
+
+	return cmd
+}
+
+func validateAlias(root *cobra.Command, name, expansion string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	if plugincmd.IsBuiltin(root, name) {
+		return fmt.Errorf("%q is a built-in command", name)
+	}
+	if alias.IsShell(expansion) {
+		if strings.TrimSpace(strings.TrimPrefix(expansion, alias.ShellPrefix)) == "" {
+			return errors.New("empty shell command")
+		}
+		return nil
+	}
+
+	words, err := shellwords.Split(expansion)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return errors.New("empty expansion")
+	}
+	if _, ok := plugin.Find(words[0]); !plugincmd.IsBuiltin(root, words[0]) && !ok {
+		return fmt.Errorf("%q is not a baepo command, use --shell to run other programs", words[0])
+	}
+	return nil
+}
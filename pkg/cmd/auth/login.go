@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	"connectrpc.com/connect"
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
@@ -13,41 +15,61 @@ import (
 var (
 	loginEmailFlag    string
 	loginPasswordFlag string
+	loginWebFlag      bool
 )
 
 func newLoginCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "login",
-		Short:   "Login to a Baepo account",
-		Example: `baepo auth login --email <email> --password <password>`,
+		Use:   "login",
+		Short: "Login to a Baepo account",
+		Long: `Login to a Baepo account, with an email and a password or with --web.
 
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if loginEmailFlag == "" || loginPasswordFlag == "" {
-				return cmd.Help()
-			}
+The credentials of the current context are replaced by the ones of the
+account.
+
+Two-factor authentication is not supported: the API logs in with an email and
+a password in a single call, and has no second-factor challenge to answer with
+a one-time or recovery code.`,
+		Example: `baepo auth login --email <email> --password <password>
+
+# Login with single sign-on, approving a one-time code in a browser
+baepo auth login --web`,
 
+		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			a := app.FromContext(ctx)
 
-			login, err := a.AuthClient.Login(ctx, connect.NewRequest(&apiv1pb.AuthLoginRequest{
-				Email:    loginEmailFlag,
-				Password: loginPasswordFlag,
-			}))
+			switch {
+			case loginWebFlag:
+				if err := webLogin(ctx, a); err != nil {
+					return err
+				}
+			case loginEmailFlag == "" || loginPasswordFlag == "":
+				return cmd.Help()
+			default:
+				login, err := a.AuthClient.Login(ctx, connect.NewRequest(&apiv1pb.AuthLoginRequest{
+					Email:    loginEmailFlag,
+					Password: loginPasswordFlag,
+				}))
 
-			if err != nil {
-				a.IOStream.Error("Login failed: %v", err)
-				return baepoerrors.AuthError
-			}
+				if err != nil {
+					return a.APIError(err, baepoerrors.AuthError, "Login failed")
+				}
 
-			a.Config.CurrentContext.SecretKey = login.Msg.SecretKey
-			a.Config.CurrentContext.UserID = login.Msg.UserId
+				a.Config.CurrentContext.SecretKey = login.Msg.SecretKey
+				a.Config.CurrentContext.UserID = login.Msg.UserId
+				a.Config.CurrentContext.AccessToken = ""
+				a.Config.CurrentContext.AccessTokenExpiresAt = time.Time{}
+			}
 
 			me, err := a.UserClient.Me(ctx, connect.NewRequest(&emptypb.Empty{}))
 			if err != nil {
-				a.IOStream.Error("Failed to get user info: %v", err)
-				return baepoerrors.AuthError
+				return a.APIError(err, baepoerrors.AuthError, "Failed to get user info")
 			}
 
+			if loginWebFlag {
+				a.Config.CurrentContext.UserID = me.Msg.User.Id
+			}
 			a.Config.CurrentContext.WorkspaceID = me.Msg.User.WorkspaceId
 
 			err = config.SaveConfig(a.Config)
@@ -64,6 +86,9 @@ func newLoginCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&loginEmailFlag, "email", "e", "", "Email address")
 	cmd.Flags().StringVarP(&loginPasswordFlag, "password", "p", "", "Password")
+	cmd.Flags().BoolVarP(&loginWebFlag, "web", "w", false, "Login in a browser, for single sign-on accounts")
+	cmd.MarkFlagsMutuallyExclusive("web", "email")
+	cmd.MarkFlagsMutuallyExclusive("web", "password")
 
 	return cmd
 }
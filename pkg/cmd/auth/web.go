@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/oauth"
+)
+
+const (
+	defaultOAuthClientID = "baepo-cli"
+	oauthScope           = "openid"
+)
+
+// webLogin logs in with the OAuth device authorization grant: the user approves
+// a code in a browser while the CLI waits for the access token, which replaces
+// the credentials of the current context.
+func webLogin(ctx context.Context, a *app.App) error {
+	c := a.Config.CurrentContext
+	if c.OAuthIssuer == "" {
+		a.IOStream.Error("No OAuth issuer configured for this context, set oauth_issuer in the config file or create the context with --oauth-issuer.")
+		return baepoerrors.ConfigError
+	}
+
+	httpClient, err := app.NewOAuthHTTPClient(c)
+	if err != nil {
+		a.IOStream.Error("Invalid transport settings in the current context: %v", err)
+		return baepoerrors.ConfigError
+	}
+
+	endpoints, err := oauth.Discover(ctx, httpClient, c.OAuthIssuer)
+	if err != nil {
+		a.IOStream.Error("Login failed: %v", err)
+		return baepoerrors.AuthError
+	}
+
+	client := &oauth.Client{
+		HTTPClient: httpClient,
+		ClientID:   cmp.Or(c.OAuthClientID, defaultOAuthClientID),
+		Endpoints:  *endpoints,
+	}
+
+	da, err := client.Authorize(ctx, oauthScope)
+	if err != nil {
+		a.IOStream.Error("Login failed: %v", err)
+		return baepoerrors.AuthError
+	}
+
+	a.IOStream.Message("First copy your one-time code: %s", da.UserCode)
+	a.IOStream.Message("Then open %s in your browser to approve the login.", cmp.Or(da.VerificationURIComplete, da.VerificationURI))
+
+	token, err := client.Wait(ctx, da)
+	switch {
+	case errors.Is(err, context.Canceled):
+		a.IOStream.Error("Login cancelled.")
+		return baepoerrors.CancelError
+	case errors.Is(err, oauth.ErrExpired):
+		a.IOStream.Error("Login failed: %v", err)
+		return baepoerrors.TimeoutError
+	case err != nil:
+		a.IOStream.Error("Login failed: %v", err)
+		return baepoerrors.AuthError
+	}
+
+	c.AccessToken = token.AccessToken
+	c.AccessTokenExpiresAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.AccessTokenExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	c.SecretKey = ""
+	return nil
+}
@@ -2,10 +2,13 @@ package contextcmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
 	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +18,8 @@ func newCreateCmd() *cobra.Command {
 	var secretKey string
 	var current bool
 	var url string
+	var transport config.Context
+	var headers []string
 
 	cmd := &cobra.Command{
 		Use:   "create",
@@ -22,7 +27,14 @@ func newCreateCmd() *cobra.Command {
 		Example: `
 # Create a blank new context (you will need to login then)
 baepo context create mycompany --current
+
+# Create a context for a self-hosted API behind a private CA and a proxy
+baepo context create onprem --url https://baepo.corp.internal/ --ca-file /etc/ssl/corp-ca.pem --proxy http://proxy.corp:3128 --header X-Tenant=corp
+
+# Create a context for an API behind a gRPC-only load balancer
+baepo context create grpc --url https://grpc.baepo.corp.internal/ --protocol grpc --compression gzip
 		`,
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			a := app.FromContext(ctx)
@@ -62,12 +74,49 @@ baepo context create mycompany --current
 				newContext.URL = url
 			}
 
+			newContext.CAFile = transport.CAFile
+			newContext.ClientCert = transport.ClientCert
+			newContext.ClientKey = transport.ClientKey
+			newContext.InsecureSkipVerify = transport.InsecureSkipVerify
+			newContext.Proxy = transport.Proxy
+			newContext.Protocol = transport.Protocol
+			newContext.Encoding = transport.Encoding
+			newContext.Compression = transport.Compression
+			newContext.OAuthIssuer = transport.OAuthIssuer
+			newContext.OAuthClientID = transport.OAuthClientID
+			if len(headers) > 0 {
+				newContext.Headers = make(map[string]string, len(headers))
+				for _, h := range headers {
+					k, v, ok := strings.Cut(h, "=")
+					if !ok || k == "" {
+						a.IOStream.Error("Invalid header %q, expected NAME=VALUE.", h)
+						return baepoerrors.InvalidArgsError
+					}
+					newContext.Headers[k] = v
+				}
+			}
+
+			if _, err := app.NewHTTPClient(&newContext); err != nil {
+				a.IOStream.Error("Invalid transport settings: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+			if _, err := app.ProtocolClientOptions(&newContext); err != nil {
+				a.IOStream.Error("Invalid protocol settings: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+
 			a.Config.Contexts[name] = &newContext
 
 			if current {
 				a.Config.CurrentContext = &newContext
 			}
 
+			if a.DryRun {
+				a.IOStream.Object(&helper.ContextFmt{Name: name, Current: current, Value: newContext}, helper.ContextFmtMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, the config was not saved.")
+				return nil
+			}
+
 			err := config.SaveConfig(a.Config)
 			if err != nil {
 				a.IOStream.Error("Failed to save config: %v", err)
@@ -78,7 +127,7 @@ baepo context create mycompany --current
 			if current {
 				message = fmt.Sprintf("Context '%s' created and set as current context.", name)
 			}
-			a.IOStream.Message(message)
+			a.IOStream.Message("%s", message)
 
 			return nil
 		},
@@ -88,6 +137,17 @@ baepo context create mycompany --current
 	cmd.Flags().StringVar(&userID, "user-id", "", "User ID")
 	cmd.Flags().StringVarP(&secretKey, "s", "", "", "Secret Key")
 	cmd.Flags().StringVarP(&url, "url", "u", "", "Baepo API URL")
+	cmd.Flags().StringVar(&transport.CAFile, "ca-file", "", "PEM file of a CA to trust in addition to the system ones")
+	cmd.Flags().StringVar(&transport.ClientCert, "client-cert", "", "PEM client certificate for mutual TLS")
+	cmd.Flags().StringVar(&transport.ClientKey, "client-key", "", "PEM client key for mutual TLS")
+	cmd.Flags().BoolVar(&transport.InsecureSkipVerify, "insecure-skip-verify", false, "Disable TLS certificate verification (unsafe)")
+	cmd.Flags().StringVar(&transport.Proxy, "proxy", "", "Proxy URL to reach the API through")
+	cmd.Flags().StringVar(&transport.Protocol, "protocol", "", "Protocol used to call the API: connect (default), grpc or grpcweb")
+	cmd.Flags().StringVar(&transport.Encoding, "encoding", "", "Encoding of messages: proto (default) or json")
+	cmd.Flags().StringVar(&transport.Compression, "compression", "", "Compression of messages: gzip or none")
+	cmd.Flags().StringVar(&transport.OAuthIssuer, "oauth-issuer", "", "Authorization server of auth login --web, required to use it")
+	cmd.Flags().StringVar(&transport.OAuthClientID, "oauth-client-id", "", "Client ID of the CLI at the authorization server")
+	cmd.Flags().StringArrayVar(&headers, "header", []string{}, "Header added to every request, as NAME=VALUE (can be repeated)")
 	cmd.Flags().BoolVarP(&current, "current", "c", false, "Set this context as the current context")
 
 	return cmd
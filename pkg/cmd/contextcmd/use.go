@@ -4,13 +4,16 @@ import (
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
 	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
 	"github.com/spf13/cobra"
 )
 
 func newUseCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "use",
-		Short: "Use context",
+		Use:         "use",
+		Short:       "Use context",
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			a := app.FromContext(ctx)
@@ -30,6 +33,12 @@ func newUseCmd() *cobra.Command {
 
 			a.Config.Context = name
 
+			if a.DryRun {
+				a.IOStream.Object(&helper.ContextFmt{Name: name, Current: true, Value: *a.Config.Contexts[name]}, helper.ContextFmtMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, the config was not saved.")
+				return nil
+			}
+
 			err := config.SaveConfig(a.Config)
 			if err != nil {
 				a.IOStream.Error("Failed to save config: %v", err)
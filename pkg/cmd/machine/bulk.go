@@ -0,0 +1,163 @@
+package machine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+const defaultBulkConcurrency = 4
+
+// machineSelector describes how a bulk command picks the machines it acts on
+// when no explicit IDs are given.
+type machineSelector struct {
+	All    bool
+	States []string
+}
+
+// resolveMachineIDs returns the machine IDs targeted by a bulk command. IDs can
+// be given as arguments, read from stdin when an argument is "-", or selected
+// from the workspace with --all and --state.
+func resolveMachineIDs(ctx context.Context, a *app.App, args []string, sel machineSelector) ([]string, error) {
+	selecting := sel.All || len(sel.States) > 0
+	if selecting && len(args) > 0 {
+		return nil, fmt.Errorf("machine IDs cannot be combined with --all or --state")
+	}
+
+	if !selecting {
+		ids := make([]string, 0, len(args))
+		for _, arg := range args {
+			if arg != "-" {
+				ids = append(ids, arg)
+				continue
+			}
+
+			stdinIDs, err := readMachineIDs(a.IOStream.Stdin)
+			if err != nil {
+				return nil, fmt.Errorf("reading machine IDs from stdin: %w", err)
+			}
+			ids = append(ids, stdinIDs...)
+		}
+		return dedupe(ids), nil
+	}
+
+	states := make([]corev1pb.MachineState, 0, len(sel.States))
+	for _, s := range sel.States {
+		state, err := helper.ParseMachineState(s)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+
+	list, err := a.MachineClient.List(ctx, connect.NewRequest(&apiv1pb.MachineListRequest{
+		WorkspaceId: a.WorkspaceID(),
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("listing machines: %w", err)
+	}
+
+	ids := make([]string, 0, len(list.Msg.Machines))
+	for _, m := range list.Msg.Machines {
+		if len(states) > 0 && !slices.Contains(states, m.GetState()) {
+			continue
+		}
+		// Without an explicit state filter, already terminated machines are skipped.
+		if len(states) == 0 && m.GetState() == corev1pb.MachineState_MachineState_Terminated {
+			continue
+		}
+		ids = append(ids, m.GetId())
+	}
+
+	return ids, nil
+}
+
+// confirmSelection asks before acting on more than one machine picked by --all
+// or --state, unless yes is set, as a selector can match far more machines
+// than expected. It reports whether to go on.
+func confirmSelection(ios *iostream.IOStream, sel machineSelector, verb string, ids []string, yes bool) (bool, error) {
+	if yes || len(ids) < 2 || (!sel.All && len(sel.States) == 0) {
+		return true, nil
+	}
+	return ios.Confirm("%d machines will be %s. Continue?", len(ids), verb)
+}
+
+// readMachineIDs reads whitespace separated machine IDs, ignoring blank lines
+// and lines starting with '#'.
+func readMachineIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, strings.Fields(line)...)
+	}
+	return ids, scanner.Err()
+}
+
+func dedupe(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// runBulk calls fn for every machine ID with at most concurrency calls in flight.
+// Results are returned in the same order as ids.
+func runBulk(ctx context.Context, ids []string, concurrency int, fn func(ctx context.Context, id string) error) []*helper.MachineResultFmt {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*helper.MachineResultFmt, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := &helper.MachineResultFmt{MachineID: id, Outcome: helper.MachineResultOK}
+			if err := fn(ctx, id); err != nil {
+				result.Outcome = helper.MachineResultFailed
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+func countFailed(results []*helper.MachineResultFmt) int {
+	failed := 0
+	for _, r := range results {
+		if r.Outcome == helper.MachineResultFailed {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,108 @@
+package machine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+func TestReadMachineIDs(t *testing.T) {
+	input := "m1 m2\n\n# a comment\n  m3  \n"
+
+	ids, err := readMachineIDs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"m1", "m2", "m3"}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}
+
+func TestRunBulk(t *testing.T) {
+	ids := []string{"m1", "m2", "m3", "m4", "m5", "m6"}
+
+	var inFlight, maxInFlight int32
+	results := runBulk(context.Background(), ids, 2, func(ctx context.Context, id string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if id == "m3" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", maxInFlight)
+	}
+
+	if len(results) != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), len(results))
+	}
+
+	for i, r := range results {
+		if r.MachineID != ids[i] {
+			t.Errorf("Expected result %d to be for %s, got %s", i, ids[i], r.MachineID)
+		}
+	}
+
+	if results[2].Outcome != helper.MachineResultFailed || results[2].Error != "boom" {
+		t.Errorf("Expected m3 to fail with boom, got %+v", results[2])
+	}
+
+	if failed := countFailed(results); failed != 1 {
+		t.Errorf("Expected 1 failure, got %d", failed)
+	}
+}
+
+func TestConfirmSelection(t *testing.T) {
+	all := machineSelector{All: true}
+	ids := []string{"m1", "m2", "m3"}
+
+	for name, tc := range map[string]struct {
+		sel      machineSelector
+		ids      []string
+		yes      bool
+		answer   string
+		expected bool
+	}{
+		"refused without --yes":   {sel: all, ids: ids, expected: false},
+		"confirmed":               {sel: all, ids: ids, answer: "y\n", expected: true},
+		"--yes":                   {sel: all, ids: ids, yes: true, expected: true},
+		"--state":                 {sel: machineSelector{States: []string{"error"}}, ids: ids, expected: false},
+		"single selected machine": {sel: all, ids: ids[:1], expected: true},
+		"explicit IDs":            {ids: ids, expected: true},
+	} {
+		var stderr bytes.Buffer
+		ios := iostream.New(false)
+		ios.Stdin = strings.NewReader(tc.answer)
+		ios.Stderr = &stderr
+
+		ok, err := confirmSelection(ios, tc.sel, "terminated", tc.ids, tc.yes)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if ok != tc.expected {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, ok)
+		}
+		if !ok && !strings.Contains(stderr.String(), "3 machines") {
+			t.Errorf("%s: expected the prompt to give the number of machines, got %q", name, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package machine
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+const containerFlagUsage = `Container definition as comma separated key=value pairs, can be repeated.
+Keys: image, env (KEY=VALUE pairs separated by ';'), env-file, cmd, health-port,
+health-path, health-method, health-initial-delay, health-period.
+Values containing commas can be quoted, e.g. cmd="sh -c 'a, b'"`
+
+// parseContainerFlag parses a --container value such as
+//
+//	image=redis:7,env=A=1;B=2,cmd="redis-server --save ''",health-port=6379
+func parseContainerFlag(value string) (*corev1pb.MachineContainerSpec, error) {
+	fields, err := splitContainerFields(value)
+	if err != nil {
+		return nil, err
+	}
+
+	container := &corev1pb.MachineContainerSpec{}
+	var http *corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec
+	healthcheck := &corev1pb.MachineContainerHealthcheckSpec{
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+	}
+	httpHealthcheck := func() *corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec {
+		if http == nil {
+			http = &corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec{Method: "GET", Path: "/"}
+		}
+		return http
+	}
+
+	for _, field := range fields {
+		key, raw, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid container field %q, expected key=value", field)
+		}
+
+		// cmd keeps its quotes so that they are interpreted as shell words.
+		v := raw
+		if key != "cmd" {
+			v = unquote(raw)
+		}
+
+		switch key {
+		case "image":
+			container.Image = v
+		case "env":
+			env, err := parseEnv(strings.Split(v, ";"))
+			if err != nil {
+				return nil, err
+			}
+			if container.Env == nil {
+				container.Env = make(map[string]string)
+			}
+			maps.Copy(container.Env, env)
+		case "env-file":
+			env, err := readEnvFile(v)
+			if err != nil {
+				return nil, err
+			}
+			if container.Env == nil {
+				container.Env = make(map[string]string)
+			}
+			maps.Copy(container.Env, env)
+		case "cmd":
+			words, err := shellwords.Split(unquote(raw))
+			if err != nil {
+				return nil, fmt.Errorf("parsing cmd: %w", err)
+			}
+			container.Command = words
+		case "health-port":
+			port, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid health-port %q", v)
+			}
+			httpHealthcheck().Port = int32(port)
+		case "health-path":
+			httpHealthcheck().Path = v
+		case "health-method":
+			httpHealthcheck().Method = v
+		case "health-initial-delay", "health-period":
+			seconds, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q, expected a number of seconds", key, v)
+			}
+			if key == "health-period" {
+				healthcheck.PeriodSeconds = int32(seconds)
+			} else {
+				healthcheck.InitialDelaySeconds = int32(seconds)
+			}
+		default:
+			return nil, fmt.Errorf("unknown container field %q", key)
+		}
+	}
+
+	if container.Image == "" {
+		return nil, fmt.Errorf("container %q is missing an image", value)
+	}
+
+	if http != nil {
+		healthcheck.Type = &corev1pb.MachineContainerHealthcheckSpec_Http{Http: http}
+		container.Healthcheck = healthcheck
+	}
+
+	return container, nil
+}
+
+// splitContainerFields splits value on the commas that are not quoted.
+func splitContainerFields(value string) ([]string, error) {
+	var fields []string
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			fields = append(fields, value[start:i])
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in container definition %q", value)
+	}
+	fields = append(fields, value[start:])
+
+	out := fields[:0]
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// unquote removes one level of matching surrounding quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
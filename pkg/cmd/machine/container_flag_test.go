@@ -0,0 +1,69 @@
+package machine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseContainerFlag(t *testing.T) {
+	c, err := parseContainerFlag(`image=redis:7,env=A=1;B=2,cmd="redis-server --save ''",health-port=6379,health-period=30`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if c.Image != "redis:7" {
+		t.Errorf("Expected image %q, got %q", "redis:7", c.Image)
+	}
+
+	if !reflect.DeepEqual(c.Env, map[string]string{"A": "1", "B": "2"}) {
+		t.Errorf("Unexpected env: %v", c.Env)
+	}
+
+	if !reflect.DeepEqual(c.Command, []string{"redis-server", "--save", ""}) {
+		t.Errorf("Unexpected command: %q", c.Command)
+	}
+
+	http := c.GetHealthcheck().GetHttp()
+	if http == nil || http.Port != 6379 || http.Path != "/" || http.Method != "GET" {
+		t.Errorf("Unexpected healthcheck: %v", c.GetHealthcheck())
+	}
+	if c.GetHealthcheck().GetPeriodSeconds() != 30 {
+		t.Errorf("Expected a period of 30s, got %d", c.GetHealthcheck().GetPeriodSeconds())
+	}
+}
+
+func TestParseContainerFlagErrors(t *testing.T) {
+	for _, value := range []string{
+		"env=A=1",                 // missing image
+		"image=nginx,unknown=1",   // unknown field
+		"image=nginx,health-port", // missing value
+		"image=nginx,health-port=http",
+		`image=nginx,cmd="unterminated`,
+	} {
+		if _, err := parseContainerFlag(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseContainersJSONIsStrict(t *testing.T) {
+	valid := `[{"image":"postgres:14","env":{"POSTGRES_PASSWORD":"secret"},"healthcheck":{"http":{"port":5432}}}]`
+	containers, err := parseContainersJSON(valid)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(containers) != 1 || containers[0].GetHealthcheck().GetHttp().GetMethod() != "GET" {
+		t.Errorf("Unexpected containers: %v", containers)
+	}
+
+	for _, invalid := range []string{
+		`[{"image":"redis","imag":"typo"}]`,
+		`[{"image":"redis","env":{"PORT":6379}}]`,
+		`[{"image":"redis","command":"redis-server"}]`,
+		`[{"env":{"A":"1"}}]`,
+	} {
+		if _, err := parseContainersJSON(invalid); err == nil {
+			t.Errorf("Expected an error for %s", invalid)
+		}
+	}
+}
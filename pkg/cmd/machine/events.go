@@ -0,0 +1,203 @@
+package machine
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newEventsCmd() *cobra.Command {
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "events <id>",
+		Short: "Show the lifecycle events of a machine",
+		Long: `Show the lifecycle events of a machine.
+
+The API does not expose the event log of a machine yet. Only the events whose
+time is kept on the machine record are shown: its start and its termination.
+Use baepo machine inspect for its current state.
+
+With --follow, the machine is polled until it is terminated, and the state
+changes observed in the meantime are printed, dated with the time they were
+observed unless the record keeps it.`,
+		Example: `baepo machine events ID
+
+# Keep watching the machine, one JSON event per line
+baepo machine events ID --follow --json`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			if len(args) < 1 {
+				a.IOStream.Error("For machine events, you must provide a machine ID.")
+				return baepoerrors.InvalidArgsError
+			}
+
+			m, err := findMachine(ctx, a, args[0])
+			if err != nil {
+				return a.APIError(err, baepoerrors.MachineError, "Inspecting machine")
+			}
+
+			printed := printMachineEvents(a, machineEvents(m), iostream.ObjectOptions{})
+			if !follow {
+				if !printed {
+					a.IOStream.Message("No events found, machine %s has not started yet.", m.Id)
+				}
+				return nil
+			}
+
+			ticker := time.NewTicker(waitPollInterval)
+			defer ticker.Stop()
+
+			for m.GetState() != corev1pb.MachineState_MachineState_Terminated {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+
+				next, err := findMachine(ctx, a, m.Id)
+				if err != nil {
+					if errors.Is(ctx.Err(), context.Canceled) {
+						return nil
+					}
+					return a.APIError(err, baepoerrors.MachineError, "Following machine")
+				}
+
+				if printMachineEvents(a, machineEventsBetween(m, next, time.Now()), iostream.ObjectOptions{NoHeaders: printed}) {
+					printed = true
+				}
+				m = next
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new events until the machine is terminated")
+
+	return cmd
+}
+
+func findMachine(ctx context.Context, a *app.App, machineID string) (*apiv1pb.Machine, error) {
+	res, err := a.MachineClient.FindById(ctx, connect.NewRequest(&apiv1pb.MachineFindByIdRequest{
+		MachineId: machineID,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return res.Msg.Machine, nil
+}
+
+// printMachineEvents writes events as a table, or as one JSON document per
+// line with --json so that followed output can be streamed. It reports whether
+// anything was printed.
+func printMachineEvents(a *app.App, events []*corev1pb.MachineEvent, opts iostream.ObjectOptions) bool {
+	if len(events) == 0 {
+		return false
+	}
+	if a.IOStream.JSONOutput {
+		a.IOStream.JSONLines(events)
+		return true
+	}
+	a.IOStream.Array(events, helper.MachineEventMapping(), opts)
+	return true
+}
+
+// machineEvents returns the events whose time is kept on the record of a
+// machine, sorted by time. The record does not keep its past states.
+func machineEvents(m *apiv1pb.Machine) []*corev1pb.MachineEvent {
+	events := make([]*corev1pb.MachineEvent, 0)
+
+	if m.StartedAt != nil {
+		events = append(events, startedEvent(m))
+	}
+	if m.TerminatedAt != nil {
+		events = append(events, terminatedEvent(m, m.TerminatedAt))
+	}
+
+	slices.SortStableFunc(events, func(a, b *corev1pb.MachineEvent) int {
+		return cmp.Compare(a.GetTimestamp().AsTime().UnixNano(), b.GetTimestamp().AsTime().UnixNano())
+	})
+	return events
+}
+
+// machineEventsBetween returns the events that explain the changes between
+// two records of the same machine. Changes that carry no time of their own are
+// dated with now, the time they were observed.
+func machineEventsBetween(prev, next *apiv1pb.Machine, now time.Time) []*corev1pb.MachineEvent {
+	at := timestamppb.New(now)
+
+	events := make([]*corev1pb.MachineEvent, 0)
+	if next.GetDesiredState() != prev.GetDesiredState() {
+		events = append(events, &corev1pb.MachineEvent{
+			Timestamp: at,
+			MachineId: next.Id,
+			Event: &corev1pb.MachineEvent_DesiredStateChangedEvent{
+				DesiredStateChangedEvent: &corev1pb.MachineEvent_DesiredStateChanged{DesiredState: next.GetDesiredState()},
+			},
+		})
+	}
+	if prev.StartedAt == nil && next.StartedAt != nil {
+		events = append(events, startedEvent(next))
+	}
+	if next.GetState() != prev.GetState() {
+		if next.GetState() == corev1pb.MachineState_MachineState_Terminated {
+			terminatedAt := next.TerminatedAt
+			if terminatedAt == nil {
+				terminatedAt = at
+			}
+			events = append(events, terminatedEvent(next, terminatedAt))
+		} else {
+			events = append(events, stateChangedEvent(next.Id, at, next.GetState()))
+		}
+	}
+	return events
+}
+
+func stateChangedEvent(machineID string, at *timestamppb.Timestamp, state corev1pb.MachineState) *corev1pb.MachineEvent {
+	return &corev1pb.MachineEvent{
+		Timestamp: at,
+		MachineId: machineID,
+		Event: &corev1pb.MachineEvent_StateChangedEvent{
+			StateChangedEvent: &corev1pb.MachineEvent_StateChanged{State: state},
+		},
+	}
+}
+
+func startedEvent(m *apiv1pb.Machine) *corev1pb.MachineEvent {
+	return &corev1pb.MachineEvent{
+		Timestamp: m.StartedAt,
+		MachineId: m.Id,
+		Event: &corev1pb.MachineEvent_Started_{
+			Started: &corev1pb.MachineEvent_Started{ExpiresAt: m.ExpiresAt},
+		},
+	}
+}
+
+func terminatedEvent(m *apiv1pb.Machine, at *timestamppb.Timestamp) *corev1pb.MachineEvent {
+	return &corev1pb.MachineEvent{
+		Timestamp: at,
+		MachineId: m.Id,
+		Event: &corev1pb.MachineEvent_TerminatedEvent{
+			TerminatedEvent: &corev1pb.MachineEvent_Terminated{
+				Cause:              m.GetTerminationCause(),
+				TerminationDetails: m.TerminationDetails,
+			},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package machine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func eventTypes(events []*corev1pb.MachineEvent) []string {
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = helper.MachineEventTypeToHumanString(e)
+	}
+	return types
+}
+
+func TestMachineEvents(t *testing.T) {
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	cause := corev1pb.MachineTerminationCause_MachineTerminationCause_Expired
+	m := &apiv1pb.Machine{
+		Id:               "m1",
+		State:            corev1pb.MachineState_MachineState_Terminated,
+		CreatedAt:        timestamppb.New(start),
+		StartedAt:        timestamppb.New(start.Add(time.Minute)),
+		TerminatedAt:     timestamppb.New(start.Add(time.Hour)),
+		TerminationCause: &cause,
+	}
+
+	got := eventTypes(machineEvents(m))
+	expected := []string{"Started", "Terminated"}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, got)
+			break
+		}
+	}
+
+	running := &apiv1pb.Machine{
+		Id:        "m2",
+		State:     corev1pb.MachineState_MachineState_Running,
+		CreatedAt: timestamppb.New(start),
+		UpdatedAt: timestamppb.New(start.Add(time.Hour)),
+	}
+	if events := machineEvents(running); len(events) != 0 {
+		t.Errorf("Expected no events for times the record does not keep, got %v", eventTypes(events))
+	}
+}
+
+func TestMachineEventsBetween(t *testing.T) {
+	start := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	prev := &apiv1pb.Machine{
+		Id:           "m1",
+		State:        corev1pb.MachineState_MachineState_Pending,
+		DesiredState: corev1pb.MachineDesiredState_MachineDesiredState_Running,
+		UpdatedAt:    timestamppb.New(start),
+	}
+	next := &apiv1pb.Machine{
+		Id:           "m1",
+		State:        corev1pb.MachineState_MachineState_Running,
+		DesiredState: corev1pb.MachineDesiredState_MachineDesiredState_Running,
+		StartedAt:    timestamppb.New(start.Add(time.Minute)),
+		UpdatedAt:    timestamppb.New(start.Add(time.Minute)),
+	}
+
+	events := machineEventsBetween(prev, next, start.Add(time.Hour))
+	got := eventTypes(events)
+	if len(got) != 2 || got[0] != "Started" || got[1] != "State Changed" {
+		t.Fatalf("Expected [Started State Changed], got %v", got)
+	}
+	if !events[0].GetTimestamp().AsTime().Equal(start.Add(time.Minute)) {
+		t.Errorf("Expected the start to be dated with the start time, got %v", events[0].GetTimestamp().AsTime())
+	}
+	if !events[1].GetTimestamp().AsTime().Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the state change to be dated with the time it was observed, got %v", events[1].GetTimestamp().AsTime())
+	}
+
+	if events := machineEventsBetween(next, next, start.Add(time.Hour)); len(events) != 0 {
+		t.Errorf("Expected no events for an unchanged machine, got %v", eventTypes(events))
+	}
+}
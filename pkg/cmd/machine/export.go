@@ -0,0 +1,66 @@
+package machine
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export <id>",
+		Short: "Export a machine as a reusable spec",
+		Example: `# Export a machine as YAML
+baepo machine export ID > machine.yaml
+
+# Recreate it later
+baepo machine create --file machine.yaml`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			if len(args) < 1 {
+				a.IOStream.Error("For machine export, you must provide a machine ID.")
+				return baepoerrors.InvalidArgsError
+			}
+
+			return exportMachine(ctx, a, args[0], format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Output format, yaml or json (defaults to json with --json, yaml otherwise)")
+
+	return cmd
+}
+
+// exportMachine writes the spec file of a machine to stdout.
+func exportMachine(ctx context.Context, a *app.App, machineID string, format string) error {
+	if format == "" {
+		format = "yaml"
+		if a.IOStream.JSONOutput {
+			format = "json"
+		}
+	}
+
+	m, err := a.MachineClient.FindById(ctx, connect.NewRequest(&apiv1pb.MachineFindByIdRequest{
+		MachineId: machineID,
+	}))
+	if err != nil {
+		return a.APIError(err, baepoerrors.MachineError, "Inspecting machine")
+	}
+
+	f := newSpecFile(m.Msg.Machine.GetName(), m.Msg.Machine.GetSpec())
+	if err := writeSpecFile(a.IOStream.Stdout, f, format); err != nil {
+		a.IOStream.Error("Exporting machine: %v", err)
+		return baepoerrors.InvalidArgsError
+	}
+
+	return nil
+}
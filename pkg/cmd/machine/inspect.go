@@ -1,7 +1,7 @@
 package machine
 
 import (
-	"fmt"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
@@ -12,11 +12,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// expiryWarningThreshold is how close to its expiry a machine has to be for
+// inspect to warn about it.
+const expiryWarningThreshold = 15 * time.Minute
+
 func newInspectCmd() *cobra.Command {
+	var export bool
+	var format string
+
 	cmd := &cobra.Command{
-		Use:     "inspect <id>",
-		Short:   "Inspect a machine",
-		Example: `baepo machine inspect <id>`,
+		Use:   "inspect <id>",
+		Short: "Inspect a machine",
+		Example: `baepo machine inspect <id>
+
+# Print a spec that can be fed back to machine create --file
+baepo machine inspect <id> --export`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -27,20 +37,41 @@ func newInspectCmd() *cobra.Command {
 				return baepoerrors.InvalidArgsError
 			}
 
+			if export {
+				return exportMachine(ctx, a, args[0], format)
+			}
+
 			m, err := a.MachineClient.FindById(ctx, connect.NewRequest(&apiv1pb.MachineFindByIdRequest{
 				MachineId: args[0],
 			}))
 
 			if err != nil {
-				a.IOStream.Error(fmt.Sprintf("Inspecting machines: %v", err))
-				return baepoerrors.MachineError
+				return a.APIError(err, baepoerrors.MachineError, "Inspecting machine")
 			}
 
+			warnIfExpiring(a, m.Msg.Machine)
 			a.IOStream.Object(m.Msg.Machine, helper.MachineMapping(), iostream.ObjectOptions{Full: true})
 
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&export, "export", false, "Export the machine as a reusable spec instead of inspecting it")
+	cmd.Flags().StringVar(&format, "format", "", "Export format, yaml or json (defaults to json with --json, yaml otherwise)")
+
 	return cmd
 }
+
+func warnIfExpiring(a *app.App, m *apiv1pb.Machine) {
+	if m.GetExpiresAt() == nil || m.GetTerminatedAt() != nil {
+		return
+	}
+
+	left := time.Until(m.GetExpiresAt().AsTime())
+	switch {
+	case left <= 0:
+		a.IOStream.Warning("Machine %s has expired and will be terminated.", m.GetId())
+	case left < expiryWarningThreshold:
+		a.IOStream.Warning("Machine %s expires in %s.", m.GetId(), helper.DurationToHumanString(left))
+	}
+}
@@ -0,0 +1,47 @@
+package machine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestWarnIfExpiring(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		machine  *apiv1pb.Machine
+		expected string
+	}{
+		"no expiry":       {machine: &apiv1pb.Machine{Id: "m1"}},
+		"far from expiry": {machine: &apiv1pb.Machine{Id: "m1", ExpiresAt: timestamppb.New(now.Add(time.Hour))}},
+		"about to expire": {machine: &apiv1pb.Machine{Id: "m1", ExpiresAt: timestamppb.New(now.Add(10*time.Minute + 30*time.Second))}, expected: "Machine m1 expires in 10m."},
+		"expired":         {machine: &apiv1pb.Machine{Id: "m1", ExpiresAt: timestamppb.New(now.Add(-time.Minute))}, expected: "Machine m1 has expired and will be terminated."},
+		"terminated": {machine: &apiv1pb.Machine{
+			Id:           "m1",
+			ExpiresAt:    timestamppb.New(now.Add(-time.Minute)),
+			TerminatedAt: timestamppb.New(now),
+		}},
+	}
+
+	for name, tt := range tests {
+		var stderr bytes.Buffer
+		ios := iostream.New(false)
+		ios.Stderr = &stderr
+
+		warnIfExpiring(&app.App{IOStream: ios}, tt.machine)
+
+		got := stderr.String()
+		switch {
+		case tt.expected == "" && got != "":
+			t.Errorf("%s: expected no warning, got %q", name, got)
+		case !strings.Contains(got, tt.expected):
+			t.Errorf("%s: expected warning %q, got %q", name, tt.expected, got)
+		}
+	}
+}
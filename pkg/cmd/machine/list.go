@@ -1,8 +1,6 @@
 package machine
 
 import (
-	"fmt"
-
 	"connectrpc.com/connect"
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
@@ -24,12 +22,11 @@ func newListCmd() *cobra.Command {
 			a := app.FromContext(ctx)
 
 			list, err := a.MachineClient.List(ctx, connect.NewRequest(&apiv1pb.MachineListRequest{
-				WorkspaceId: a.Config.CurrentContext.WorkspaceID,
+				WorkspaceId: a.WorkspaceID(),
 			}))
 
 			if err != nil {
-				a.IOStream.Error(fmt.Sprintf("Listing machines: %v", err))
-				return baepoerrors.MachineError
+				return a.APIError(err, baepoerrors.MachineError, "Listing machines")
 			}
 
 			if len(list.Msg.Machines) == 0 {
@@ -10,7 +10,13 @@ func NewMachineCmd() *cobra.Command {
 
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newInspectCmd())
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newEventsCmd())
+	cmd.AddCommand(newStatsCmd())
 	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newUpdateCmd())
+	cmd.AddCommand(newStartCmd())
+	cmd.AddCommand(newStopCmd())
 	cmd.AddCommand(newTerminateCmd())
 
 	return cmd
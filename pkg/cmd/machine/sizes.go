@@ -0,0 +1,69 @@
+package machine
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+)
+
+// builtinSizes are the presets available with --size. They can be overridden
+// or extended by the sizes section of the config file.
+var builtinSizes = map[string]*config.Size{
+	"small":  {Cpus: 1, Memory: "1GiB"},
+	"medium": {Cpus: 2, Memory: "4GiB"},
+	"large":  {Cpus: 4, Memory: "8GiB"},
+}
+
+// sizes returns the size presets, user defined sizes taking precedence over the
+// built-in ones.
+func sizes(cfg *config.Config) map[string]*config.Size {
+	sizes := maps.Clone(builtinSizes)
+	if cfg != nil {
+		maps.Copy(sizes, cfg.Sizes)
+	}
+	return sizes
+}
+
+// lookupSize resolves a size preset.
+func lookupSize(cfg *config.Config, name string) (uint32, uint64, error) {
+	sizes := sizes(cfg)
+
+	size, ok := sizes[name]
+	if !ok || size == nil {
+		names := slices.Sorted(maps.Keys(sizes))
+		return 0, 0, fmt.Errorf("unknown size %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	memoryMB, err := helper.ParseMemoryMB(size.Memory)
+	if err != nil {
+		return 0, 0, fmt.Errorf("size %q: %w", name, err)
+	}
+	if size.Cpus == 0 || memoryMB == 0 {
+		return 0, 0, fmt.Errorf("size %q must have CPUs and memory", name)
+	}
+	return size.Cpus, memoryMB, nil
+}
+
+// validateResources checks that a CPU and memory combination is an allowed
+// machine size, that is the one of a size preset.
+func validateResources(cfg *config.Config, cpus uint32, memoryMB uint64) error {
+	allowed := make([]string, 0)
+	for _, name := range slices.Sorted(maps.Keys(sizes(cfg))) {
+		sizeCPUs, sizeMemoryMB, err := lookupSize(cfg, name)
+		if err != nil {
+			// Invalid sizes are reported when used with --size.
+			continue
+		}
+		if sizeCPUs == cpus && sizeMemoryMB == memoryMB {
+			return nil
+		}
+		allowed = append(allowed, fmt.Sprintf("%s (%d CPU, %s)", name, sizeCPUs, helper.MemoryMBToHumanString(sizeMemoryMB)))
+	}
+
+	return fmt.Errorf("%d CPU with %s of memory is not an allowed machine size, allowed sizes are %s. Sizes can be added in the sizes section of the config file",
+		cpus, helper.MemoryMBToHumanString(memoryMB), strings.Join(allowed, ", "))
+}
@@ -0,0 +1,70 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+func TestLookupSize(t *testing.T) {
+	cfg := &config.Config{Sizes: map[string]*config.Size{
+		"medium": {Cpus: 3, Memory: "3000"},
+		"gpu":    {Cpus: 12, Memory: "96GiB"},
+		"empty":  {Memory: "1GiB"},
+	}}
+
+	for name, want := range map[string][2]uint64{
+		"small":  {1, 1024},
+		"medium": {3, 3000},
+		"gpu":    {12, 96 * 1024},
+	} {
+		cpus, memoryMB, err := lookupSize(cfg, name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if uint64(cpus) != want[0] || memoryMB != want[1] {
+			t.Errorf("%s: expected %d CPUs and %d MB, got %d CPUs and %d MB", name, want[0], want[1], cpus, memoryMB)
+		}
+	}
+
+	for _, name := range []string{"huge", "empty"} {
+		if _, _, err := lookupSize(cfg, name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestValidateSpecLeavesSizesToTheServer(t *testing.T) {
+	spec := &corev1pb.MachineSpec{
+		Cpus:       3,
+		MemoryMb:   1000,
+		Containers: []*corev1pb.MachineContainerSpec{{Image: "nginx"}},
+	}
+	if err := validateSpec(spec); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	spec.Cpus = 0
+	if err := validateSpec(spec); err == nil {
+		t.Error("Expected a spec without CPUs to be rejected")
+	}
+}
+
+func TestValidateResources(t *testing.T) {
+	cfg := &config.Config{Sizes: map[string]*config.Size{
+		"gpu": {Cpus: 12, Memory: "96GiB"},
+	}}
+
+	for _, ok := range [][2]uint64{{1, 1024}, {4, 8192}, {12, 96 * 1024}} {
+		if err := validateResources(cfg, uint32(ok[0]), ok[1]); err != nil {
+			t.Errorf("%d CPU and %d MB: unexpected error: %v", ok[0], ok[1], err)
+		}
+	}
+	for _, bad := range [][2]uint64{{1, 4096}, {3, 1024}, {12, 1024}} {
+		if err := validateResources(cfg, uint32(bad[0]), bad[1]); err == nil {
+			t.Errorf("%d CPU and %d MB: expected an error", bad[0], bad[1])
+		}
+	}
+}
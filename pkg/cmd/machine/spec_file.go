@@ -0,0 +1,165 @@
+package machine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// specFile is the serializable form of a machine, without any field managed by
+// the server (IDs, node, timestamps, states). It is emitted by machine export
+// and read back by machine create --file. Its containers use the same format as
+// the --containers flag.
+type specFile struct {
+	Name       string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Cpus       uint32          `json:"cpus" yaml:"cpus"`
+	MemoryMB   uint64          `json:"memory_mb" yaml:"memory_mb"`
+	Timeout    *uint64         `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Containers []containerFile `json:"containers" yaml:"containers"`
+}
+
+type containerFile struct {
+	Image       string            `json:"image" yaml:"image"`
+	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Command     []string          `json:"command,omitempty" yaml:"command,omitempty"`
+	Healthcheck *healthcheckFile  `json:"healthcheck,omitempty" yaml:"healthcheck,omitempty"`
+}
+
+type healthcheckFile struct {
+	InitialDelaySeconds int32                `json:"initial_delay_seconds,omitempty" yaml:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int32                `json:"period_seconds,omitempty" yaml:"period_seconds,omitempty"`
+	HTTP                *httpHealthcheckFile `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+type httpHealthcheckFile struct {
+	Method  string            `json:"method,omitempty" yaml:"method,omitempty"`
+	Path    string            `json:"path,omitempty" yaml:"path,omitempty"`
+	Port    int32             `json:"port,omitempty" yaml:"port,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+func newSpecFile(name string, spec *corev1pb.MachineSpec) *specFile {
+	f := &specFile{
+		Name:       name,
+		Cpus:       spec.GetCpus(),
+		MemoryMB:   spec.GetMemoryMb(),
+		Timeout:    spec.Timeout,
+		Containers: make([]containerFile, 0, len(spec.GetContainers())),
+	}
+
+	for _, c := range spec.GetContainers() {
+		cf := containerFile{
+			Image:   c.Image,
+			Env:     c.Env,
+			Command: c.Command,
+		}
+		if hc := c.Healthcheck; hc != nil {
+			cf.Healthcheck = &healthcheckFile{
+				InitialDelaySeconds: hc.InitialDelaySeconds,
+				PeriodSeconds:       hc.PeriodSeconds,
+			}
+			if h := hc.GetHttp(); h != nil {
+				cf.Healthcheck.HTTP = &httpHealthcheckFile{
+					Method:  h.Method,
+					Path:    h.Path,
+					Port:    h.Port,
+					Headers: h.Headers,
+				}
+			}
+		}
+		f.Containers = append(f.Containers, cf)
+	}
+
+	return f
+}
+
+func (f *specFile) toProto() *corev1pb.MachineSpec {
+	spec := &corev1pb.MachineSpec{
+		Cpus:       f.Cpus,
+		MemoryMb:   f.MemoryMB,
+		Timeout:    f.Timeout,
+		Containers: make([]*corev1pb.MachineContainerSpec, 0, len(f.Containers)),
+	}
+	for _, c := range f.Containers {
+		spec.Containers = append(spec.Containers, c.toProto())
+	}
+	return spec
+}
+
+func (c containerFile) toProto() *corev1pb.MachineContainerSpec {
+	container := &corev1pb.MachineContainerSpec{
+		Image:   c.Image,
+		Env:     c.Env,
+		Command: c.Command,
+	}
+	if hc := c.Healthcheck; hc != nil {
+		container.Healthcheck = &corev1pb.MachineContainerHealthcheckSpec{
+			InitialDelaySeconds: hc.InitialDelaySeconds,
+			PeriodSeconds:       hc.PeriodSeconds,
+		}
+		if h := hc.HTTP; h != nil {
+			method := h.Method
+			if method == "" {
+				method = "GET"
+			}
+			container.Healthcheck.Type = &corev1pb.MachineContainerHealthcheckSpec_Http{
+				Http: &corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec{
+					Method:  method,
+					Path:    h.Path,
+					Port:    h.Port,
+					Headers: h.Headers,
+				},
+			}
+		}
+	}
+	return container
+}
+
+// writeSpecFile encodes f to w as "yaml" or "json".
+func writeSpecFile(w io.Writer, f *specFile, format string) error {
+	switch format {
+	case "yaml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(f); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(f)
+	default:
+		return fmt.Errorf("unknown format %q, expected yaml or json", format)
+	}
+}
+
+// readSpecFile reads a spec file in YAML or JSON format from path, or from
+// stdin when path is "-". Unknown fields are rejected.
+func readSpecFile(path string, stdin io.Reader) (*specFile, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is a subset of YAML, so a single decoder handles both formats.
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var f specFile
+	if err := decoder.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parsing spec file: %w", err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,73 @@
+package machine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSpecFileRoundTrip(t *testing.T) {
+	spec := &corev1pb.MachineSpec{
+		Cpus:     2,
+		MemoryMb: 2048,
+		Containers: []*corev1pb.MachineContainerSpec{
+			{
+				Image:   "postgres:14",
+				Env:     map[string]string{"POSTGRES_PASSWORD": "secret"},
+				Command: []string{"postgres", "-c", "fsync=off"},
+				Healthcheck: &corev1pb.MachineContainerHealthcheckSpec{
+					InitialDelaySeconds: 5,
+					PeriodSeconds:       10,
+					Type: &corev1pb.MachineContainerHealthcheckSpec_Http{
+						Http: &corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec{
+							Method: "GET",
+							Path:   "/health",
+							Port:   8080,
+						},
+					},
+				},
+			},
+			{Image: "redis:alpine"},
+		},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		var buf bytes.Buffer
+		if err := writeSpecFile(&buf, newSpecFile("db", spec), format); err != nil {
+			t.Fatalf("%s: failed to write spec file: %v", format, err)
+		}
+
+		path := filepath.Join(t.TempDir(), "machine."+format)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := readSpecFile(path, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to read spec file: %v", format, err)
+		}
+
+		if f.Name != "db" {
+			t.Errorf("%s: expected name %q, got %q", format, "db", f.Name)
+		}
+
+		if got := f.toProto(); !proto.Equal(got, spec) {
+			t.Errorf("%s: spec changed after round trip:\nexpected %v\ngot      %v", format, spec, got)
+		}
+	}
+}
+
+func TestReadSpecFileRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "machine.yaml")
+	if err := os.WriteFile(path, []byte("cpus: 1\nmemory_mb: 512\nnode_id: n1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readSpecFile(path, nil); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
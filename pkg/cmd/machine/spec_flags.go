@@ -0,0 +1,385 @@
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/dotenv"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// specFlags holds the flags describing a MachineSpec. They are shared by every
+// command that builds or modifies a spec so that create and update accept the
+// exact same options.
+type specFlags struct {
+	cpus           uint32
+	memory         string
+	size           string
+	image          string
+	env            []string
+	envFiles       []string
+	command        string
+	entrypoint     string
+	healthPort     int32
+	healthPath     string
+	healthMethod   string
+	containersJSON string
+	containers     []string
+	ttl            time.Duration
+}
+
+func (f *specFlags) register(cmd *cobra.Command) {
+	cmd.Flags().Uint32Var(&f.cpus, "cpus", 1, "Number of CPUs")
+	cmd.Flags().StringVar(&f.memory, "memory", "1GiB", "Memory, e.g. 512MiB or 2GiB (a plain number is in MB)")
+	cmd.Flags().StringVar(&f.size, "size", "", "Size preset setting CPUs and memory (small, medium, large or a size from the config file)")
+	cmd.Flags().StringVar(&f.image, "image", "", "Container image")
+	cmd.Flags().StringArrayVar(&f.env, "env", []string{}, "Environment variable as KEY=VALUE, KEY=@file to read the value from a file, or KEY to pass it from the local environment")
+	cmd.Flags().StringArrayVar(&f.envFiles, "env-file", []string{}, "Read environment variables from a dotenv file")
+	cmd.Flags().StringVar(&f.command, "command", "", "Command of the container, split like a shell command line")
+	cmd.Flags().StringVar(&f.entrypoint, "entrypoint", "", "Entrypoint of the container, prepended to --command")
+	cmd.Flags().Int32Var(&f.healthPort, "health-port", 0, "Healthcheck port, the container has an HTTP healthcheck when set")
+	cmd.Flags().StringVar(&f.healthPath, "health-path", "", "Healthcheck path (default \"/\")")
+	cmd.Flags().StringVar(&f.healthMethod, "health-method", "GET", "Healthcheck method")
+	cmd.Flags().StringVar(&f.containersJSON, "containers", "", "Container definitions in JSON format")
+	cmd.Flags().StringArrayVar(&f.containers, "container", []string{}, containerFlagUsage)
+	cmd.Flags().DurationVar(&f.ttl, "ttl", 0, "Time to live after which the machine expires and is terminated, e.g. 2h")
+	cmd.MarkFlagsMutuallyExclusive("containers", "container", "image")
+}
+
+// buildSpec creates a new MachineSpec from the flags.
+func (f *specFlags) buildSpec(flags *pflag.FlagSet, cfg *config.Config) (*corev1pb.MachineSpec, error) {
+	memoryMB, err := helper.ParseMemoryMB(f.memory)
+	if err != nil {
+		return nil, err
+	}
+	spec := &corev1pb.MachineSpec{
+		Cpus:     f.cpus,
+		MemoryMb: memoryMB,
+	}
+	if err := f.applyResources(flags, cfg, spec); err != nil {
+		return nil, err
+	}
+
+	if f.containersJSON != "" {
+		containers, err := parseContainersJSON(f.containersJSON)
+		if err != nil {
+			return nil, err
+		}
+		spec.Containers = containers
+	} else if len(f.containers) > 0 {
+		containers, err := f.parseContainers()
+		if err != nil {
+			return nil, err
+		}
+		spec.Containers = containers
+	} else if f.image != "" {
+		env, err := f.buildEnv()
+		if err != nil {
+			return nil, err
+		}
+		command, err := f.buildCommand()
+		if err != nil {
+			return nil, err
+		}
+		container := &corev1pb.MachineContainerSpec{
+			Image:   f.image,
+			Env:     env,
+			Command: command,
+		}
+		healthcheck, err := f.healthcheck(flags)
+		if err != nil {
+			return nil, err
+		}
+		container.Healthcheck = healthcheck
+		spec.Containers = append(spec.Containers, container)
+	} else {
+		return nil, fmt.Errorf("either --image, --container or --containers must be specified")
+	}
+
+	return spec, validateSpec(spec)
+}
+
+// applyTo overrides the fields of an existing spec with the flags explicitly
+// set on the command line. Single container flags (--image, --env, --command,
+// --health-* ...) apply to the container at index containerIdx.
+func (f *specFlags) applyTo(flags *pflag.FlagSet, cfg *config.Config, spec *corev1pb.MachineSpec, containerIdx int) error {
+	if err := f.applyResources(flags, cfg, spec); err != nil {
+		return err
+	}
+
+	if flags.Changed("containers") {
+		containers, err := parseContainersJSON(f.containersJSON)
+		if err != nil {
+			return err
+		}
+		spec.Containers = containers
+	}
+	if flags.Changed("container") {
+		containers, err := f.parseContainers()
+		if err != nil {
+			return err
+		}
+		spec.Containers = containers
+	}
+
+	containerFlagChanged := flags.Changed("image") || flags.Changed("env") || flags.Changed("env-file") ||
+		flags.Changed("command") || flags.Changed("entrypoint") ||
+		flags.Changed("health-port") || flags.Changed("health-path") || flags.Changed("health-method")
+	if containerFlagChanged {
+		if containerIdx < 0 || containerIdx > len(spec.Containers) {
+			return fmt.Errorf("container index %d is out of range, the machine has %d container(s)", containerIdx, len(spec.Containers))
+		}
+		if containerIdx == len(spec.Containers) {
+			if !flags.Changed("image") {
+				return fmt.Errorf("--image is required to add a new container")
+			}
+			spec.Containers = append(spec.Containers, &corev1pb.MachineContainerSpec{})
+		}
+
+		container := spec.Containers[containerIdx]
+		if flags.Changed("image") {
+			container.Image = f.image
+		}
+		if flags.Changed("env") || flags.Changed("env-file") {
+			env, err := f.buildEnv()
+			if err != nil {
+				return err
+			}
+			container.Env = env
+		}
+		if flags.Changed("command") || flags.Changed("entrypoint") {
+			command, err := f.buildCommand()
+			if err != nil {
+				return err
+			}
+			container.Command = command
+		}
+		if flags.Changed("health-port") || flags.Changed("health-path") || flags.Changed("health-method") {
+			healthcheck, err := f.healthcheck(flags)
+			if err != nil {
+				return err
+			}
+			container.Healthcheck = healthcheck
+		}
+	}
+
+	return validateSpec(spec)
+}
+
+// applyResources sets the CPUs and memory of spec from --size, then from
+// --cpus and --memory when they are set explicitly, and checks that they make
+// an allowed machine size. It also sets the TTL.
+func (f *specFlags) applyResources(flags *pflag.FlagSet, cfg *config.Config, spec *corev1pb.MachineSpec) error {
+	if f.size != "" {
+		cpus, memoryMB, err := lookupSize(cfg, f.size)
+		if err != nil {
+			return err
+		}
+		spec.Cpus = cpus
+		spec.MemoryMb = memoryMB
+	}
+
+	if flags.Changed("cpus") {
+		spec.Cpus = f.cpus
+	}
+	if flags.Changed("memory") {
+		memoryMB, err := helper.ParseMemoryMB(f.memory)
+		if err != nil {
+			return err
+		}
+		spec.MemoryMb = memoryMB
+	}
+	if f.size != "" || flags.Changed("cpus") || flags.Changed("memory") {
+		if err := validateResources(cfg, spec.Cpus, spec.MemoryMb); err != nil {
+			return err
+		}
+	}
+
+	if flags.Changed("ttl") {
+		switch {
+		case f.ttl < 0:
+			return fmt.Errorf("--ttl must be positive")
+		case f.ttl == 0:
+			spec.Timeout = nil
+		case f.ttl < time.Second:
+			return fmt.Errorf("--ttl must be at least 1s")
+		default:
+			timeout := uint64(f.ttl.Round(time.Second).Seconds())
+			spec.Timeout = &timeout
+		}
+	}
+
+	return nil
+}
+
+func (f *specFlags) parseContainers() ([]*corev1pb.MachineContainerSpec, error) {
+	containers := make([]*corev1pb.MachineContainerSpec, 0, len(f.containers))
+	for _, c := range f.containers {
+		container, err := parseContainerFlag(c)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// buildCommand returns the container command made of the --entrypoint words
+// followed by the --command words. The spec has a single command field, so the
+// entrypoint is simply its first words.
+func (f *specFlags) buildCommand() ([]string, error) {
+	entrypoint, err := shellwords.Split(f.entrypoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --entrypoint: %w", err)
+	}
+	command, err := shellwords.Split(f.command)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --command: %w", err)
+	}
+	return append(entrypoint, command...), nil
+}
+
+// healthcheck returns the HTTP healthcheck described by the --health-* flags,
+// or nil when --health-port is not set.
+func (f *specFlags) healthcheck(flags *pflag.FlagSet) (*corev1pb.MachineContainerHealthcheckSpec, error) {
+	if f.healthPort <= 0 {
+		if flags.Changed("health-path") || flags.Changed("health-method") {
+			return nil, fmt.Errorf("--health-path and --health-method require --health-port")
+		}
+		return nil, nil
+	}
+
+	path := f.healthPath
+	if path == "" {
+		path = "/"
+	}
+	return &corev1pb.MachineContainerHealthcheckSpec{
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		Type: &corev1pb.MachineContainerHealthcheckSpec_Http{
+			Http: &corev1pb.MachineContainerHealthcheckSpec_HttpHealthcheckSpec{
+				Method: f.healthMethod,
+				Path:   path,
+				Port:   f.healthPort,
+			},
+		},
+	}, nil
+}
+
+func validateSpec(spec *corev1pb.MachineSpec) error {
+	if spec.Cpus == 0 {
+		return fmt.Errorf("CPUs must be greater than 0")
+	}
+	if spec.MemoryMb == 0 {
+		return fmt.Errorf("memory must be greater than 0")
+	}
+	if len(spec.Containers) == 0 {
+		return fmt.Errorf("at least one container must be specified")
+	}
+	for i, c := range spec.Containers {
+		if c.Image == "" {
+			return fmt.Errorf("container %d is missing an image", i)
+		}
+	}
+	return nil
+}
+
+// buildEnv merges the variables of the --env-file files, in order, with the
+// --env flags, which take precedence.
+func (f *specFlags) buildEnv() (map[string]string, error) {
+	env := make(map[string]string)
+	for _, path := range f.envFiles {
+		fileEnv, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(env, fileEnv)
+	}
+
+	flagEnv, err := parseEnv(f.env)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(env, flagEnv)
+
+	return env, nil
+}
+
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	defer file.Close()
+
+	env, err := dotenv.Parse(file, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("parsing env file %s: %w", path, err)
+	}
+	return env, nil
+}
+
+// parseEnv parses --env values. Each value is either KEY=VALUE, KEY=@path to
+// read the value from a file, or a bare KEY to copy it from the local
+// environment.
+func parseEnv(env []string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, e := range env {
+		key, value, hasValue := strings.Cut(e, "=")
+		if !dotenv.ValidKey(key) {
+			return nil, fmt.Errorf("invalid environment variable %q, expected KEY=VALUE, KEY=@file or KEY", e)
+		}
+
+		switch {
+		case !hasValue:
+			v, ok := os.LookupEnv(key)
+			if !ok {
+				return nil, fmt.Errorf("environment variable %s is not set locally", key)
+			}
+			out[key] = v
+		case strings.HasPrefix(value, "@"):
+			data, err := os.ReadFile(value[1:])
+			if err != nil {
+				return nil, fmt.Errorf("reading value of %s: %w", key, err)
+			}
+			out[key] = strings.TrimSuffix(string(data), "\n")
+		default:
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+// parseContainersJSON parses the JSON array accepted by --containers. Unknown
+// fields and values of the wrong type are rejected.
+func parseContainersJSON(containersJSON string) ([]*corev1pb.MachineContainerSpec, error) {
+	decoder := json.NewDecoder(strings.NewReader(containersJSON))
+	decoder.DisallowUnknownFields()
+
+	var files []containerFile
+	if err := decoder.Decode(&files); err != nil {
+		return nil, fmt.Errorf("parsing containers JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("parsing containers JSON: unexpected data after the containers array")
+	}
+
+	containers := make([]*corev1pb.MachineContainerSpec, 0, len(files))
+	for i, f := range files {
+		if f.Image == "" {
+			return nil, fmt.Errorf("container %d is missing the 'image' field", i)
+		}
+		containers = append(containers, f.toProto())
+	}
+
+	return containers, nil
+}
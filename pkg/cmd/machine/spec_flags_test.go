@@ -0,0 +1,80 @@
+package machine
+
+import (
+	"testing"
+	"time"
+
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"github.com/spf13/cobra"
+)
+
+func TestHealthcheckRequiresPort(t *testing.T) {
+	for name, tc := range map[string]struct {
+		args        []string
+		healthcheck bool
+		err         bool
+	}{
+		"no health flags": {args: []string{"--image", "nginx"}},
+		"port":            {args: []string{"--image", "nginx", "--health-port", "8080"}, healthcheck: true},
+		"path alone":      {args: []string{"--image", "nginx", "--health-path", "/up"}, err: true},
+	} {
+		var f specFlags
+		cmd := &cobra.Command{}
+		f.register(cmd)
+		if err := cmd.ParseFlags(tc.args); err != nil {
+			t.Fatal(err)
+		}
+
+		spec, err := f.buildSpec(cmd.Flags(), nil)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error %v", name, err)
+			continue
+		}
+		if err == nil && (spec.Containers[0].Healthcheck != nil) != tc.healthcheck {
+			t.Errorf("%s: expected healthcheck %v, got %v", name, tc.healthcheck, spec.Containers[0].Healthcheck)
+		}
+	}
+}
+
+func TestTTL(t *testing.T) {
+	hour := uint64(3600)
+	seconds := func(v uint64) *uint64 { return &v }
+	// The maximum TTL is enforced by the server, larger values are sent as is.
+	tenYears := 10 * 365 * 24 * time.Hour
+
+	for name, tc := range map[string]struct {
+		ttl      string
+		expected *uint64
+		err      bool
+	}{
+		"2h":                {ttl: "2h", expected: seconds(2 * hour)},
+		"rounded to second": {ttl: "90.4s", expected: seconds(90)},
+		"0 clears":          {ttl: "0"},
+		"negative":          {ttl: "-1h", err: true},
+		"below a second":    {ttl: "300ms", err: true},
+		"above server max":  {ttl: tenYears.String(), expected: seconds(uint64(tenYears.Seconds()))},
+	} {
+		var f specFlags
+		cmd := &cobra.Command{}
+		f.register(cmd)
+		if err := cmd.ParseFlags([]string{"--ttl", tc.ttl}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		spec := &corev1pb.MachineSpec{Timeout: &hour}
+		err := f.applyResources(cmd.Flags(), nil, spec)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error %v", name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		switch {
+		case tc.expected == nil && spec.Timeout != nil:
+			t.Errorf("%s: expected no timeout, got %d", name, *spec.Timeout)
+		case tc.expected != nil && (spec.Timeout == nil || *spec.Timeout != *tc.expected):
+			t.Errorf("%s: expected timeout %d, got %v", name, *tc.expected, spec.Timeout)
+		}
+	}
+}
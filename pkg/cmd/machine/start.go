@@ -0,0 +1,83 @@
+package machine
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/spf13/cobra"
+)
+
+func newStartCmd() *cobra.Command {
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "start <id>...",
+		Short: "Start a machine",
+		Long: `Start machines, printing the outcome for each of them.
+
+There is no restart command: the Baepo API cannot stop a machine without
+terminating it yet, and a terminated machine cannot be started again.`,
+		Example: `# Start a machine
+baepo machine start ID
+
+# Start multiple machines
+baepo machine start ID1 ID2
+
+# Start machines whose IDs are read from stdin
+baepo machine start -`,
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			ids, err := resolveMachineIDs(ctx, a, args, machineSelector{})
+			if err != nil {
+				return a.APIError(err, baepoerrors.InvalidArgsError, "Selecting machines")
+			}
+
+			if len(ids) == 0 {
+				a.IOStream.Error("You must provide at least one machine ID.")
+				return baepoerrors.InvalidArgsError
+			}
+
+			if a.DryRun {
+				reqs := make([]*apiv1pb.MachineStartRequest, len(ids))
+				for i, id := range ids {
+					reqs[i] = &apiv1pb.MachineStartRequest{MachineId: id}
+				}
+				a.IOStream.Array(reqs, helper.MachineStartRequestMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, %d machine(s) were not started.", len(reqs))
+				return nil
+			}
+
+			results := runBulk(ctx, ids, concurrency, func(ctx context.Context, id string) error {
+				_, err := a.MachineClient.Start(ctx, connect.NewRequest(&apiv1pb.MachineStartRequest{
+					MachineId: id,
+				}))
+				return err
+			})
+
+			a.IOStream.Array(results, helper.MachineResultFmtMapping(), iostream.ObjectOptions{Full: false})
+
+			switch failed := countFailed(results); {
+			case failed == len(results):
+				a.IOStream.Error("Failed to start %d machine(s).", failed)
+				return baepoerrors.MachineError
+			case failed > 0:
+				a.IOStream.Error("Failed to start %d of %d machine(s).", failed, len(results))
+				return baepoerrors.PartialError
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultBulkConcurrency, "Maximum number of machines started at once")
+
+	return cmd
+}
@@ -0,0 +1,137 @@
+package machine
+
+import (
+	"slices"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"github.com/spf13/cobra"
+)
+
+// statsTotal is the state of the row summing the machines that are not
+// terminated.
+const statsTotal = "Total"
+
+func newStatsCmd() *cobra.Command {
+	var watch bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show the CPUs and memory allocated to the machines of the workspace",
+		Long: `Show the number of machines of the workspace in each state, with the CPUs
+and memory given to them by their spec. The total leaves out terminated
+machines.
+
+The API does not report the resources machines actually use yet, only the ones
+they were allocated.
+
+With --watch, the stats are refreshed every --interval until interrupted,
+redrawing the screen on a terminal. With --json, each refresh is printed as a
+JSON array on its own line.`,
+		Example: `baepo machine stats
+
+# Refresh the stats every 5 seconds
+baepo machine stats --watch --interval 5s`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			if interval <= 0 {
+				a.IOStream.Error("--interval must be positive")
+				return baepoerrors.InvalidArgsError
+			}
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				list, err := a.MachineClient.List(ctx, connect.NewRequest(&apiv1pb.MachineListRequest{
+					WorkspaceId: a.WorkspaceID(),
+				}))
+				if err != nil {
+					if watch && ctx.Err() != nil {
+						return nil
+					}
+					return a.APIError(err, baepoerrors.MachineError, "Listing machines")
+				}
+				stats := machineStats(list.Msg.Machines)
+
+				switch {
+				case !watch:
+					a.IOStream.Array(stats, helper.MachineStatsFmtMapping(), iostream.ObjectOptions{})
+					return nil
+				case a.IOStream.JSONOutput:
+					a.IOStream.JSONLines([][]*helper.MachineStatsFmt{stats})
+				default:
+					if a.IOStream.CanRefresh() {
+						a.IOStream.ClearScreen()
+					}
+					a.IOStream.Message("Every %s, updated at %s", interval, time.Now().Format(time.TimeOnly))
+					a.IOStream.Array(stats, helper.MachineStatsFmtMapping(), iostream.ObjectOptions{})
+					if !a.IOStream.CanRefresh() {
+						a.IOStream.Message("")
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Keep refreshing the stats until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Time between refreshes with --watch")
+
+	return cmd
+}
+
+// machineStats sums the machines and their resources by state, in the order of
+// the lifecycle, followed by the total of the machines that are not
+// terminated.
+func machineStats(machines []*apiv1pb.Machine) []*helper.MachineStatsFmt {
+	byState := make(map[corev1pb.MachineState]*helper.MachineStatsFmt)
+	total := &helper.MachineStatsFmt{State: statsTotal}
+
+	for _, m := range machines {
+		state := m.GetState()
+		s, ok := byState[state]
+		if !ok {
+			s = &helper.MachineStatsFmt{State: helper.MachineStateToHumanString(state)}
+			byState[state] = s
+		}
+
+		addMachine(s, m)
+		if state != corev1pb.MachineState_MachineState_Terminated {
+			addMachine(total, m)
+		}
+	}
+
+	states := make([]corev1pb.MachineState, 0, len(byState))
+	for state := range byState {
+		states = append(states, state)
+	}
+	slices.Sort(states)
+
+	stats := make([]*helper.MachineStatsFmt, 0, len(states)+1)
+	for _, state := range states {
+		stats = append(stats, byState[state])
+	}
+	return append(stats, total)
+}
+
+func addMachine(s *helper.MachineStatsFmt, m *apiv1pb.Machine) {
+	s.Machines++
+	s.Cpus += uint64(m.GetSpec().GetCpus())
+	s.MemoryMB += m.GetSpec().GetMemoryMb()
+}
@@ -0,0 +1,42 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+func TestMachineStats(t *testing.T) {
+	machine := func(state corev1pb.MachineState, cpus uint32, memoryMB uint64) *apiv1pb.Machine {
+		return &apiv1pb.Machine{State: state, Spec: &corev1pb.MachineSpec{Cpus: cpus, MemoryMb: memoryMB}}
+	}
+	machines := []*apiv1pb.Machine{
+		machine(corev1pb.MachineState_MachineState_Terminated, 16, 65536),
+		machine(corev1pb.MachineState_MachineState_Running, 2, 4096),
+		machine(corev1pb.MachineState_MachineState_Pending, 1, 1024),
+		machine(corev1pb.MachineState_MachineState_Running, 4, 8192),
+	}
+
+	expected := []helper.MachineStatsFmt{
+		{State: "Pending", Machines: 1, Cpus: 1, MemoryMB: 1024},
+		{State: "Running", Machines: 2, Cpus: 6, MemoryMB: 12288},
+		{State: "Terminated", Machines: 1, Cpus: 16, MemoryMB: 65536},
+		{State: statsTotal, Machines: 3, Cpus: 7, MemoryMB: 13312},
+	}
+
+	stats := machineStats(machines)
+	if len(stats) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d", len(expected), len(stats))
+	}
+	for i, s := range stats {
+		if *s != expected[i] {
+			t.Errorf("Row %d: expected %+v, got %+v", i, expected[i], *s)
+		}
+	}
+
+	if stats := machineStats(nil); len(stats) != 1 || stats[0].Machines != 0 {
+		t.Errorf("Expected only an empty total without machines, got %v", stats)
+	}
+}
@@ -0,0 +1,28 @@
+package machine
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/spf13/cobra"
+)
+
+// newStopCmd replaces the former "stop" alias of terminate. Stopping a machine
+// while keeping it around is not exposed by the API yet, so the command refuses
+// to do anything instead of silently terminating the machine.
+func newStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:        "stop <id>...",
+		Short:      "Stop a machine without terminating it",
+		Hidden:     true,
+		Deprecated: "it no longer terminates machines, use 'baepo machine terminate' to terminate them.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			a.IOStream.Error("Stopping a machine without terminating it is not supported by the Baepo API yet. Nothing was done.")
+			return baepoerrors.InvalidArgsError
+		},
+	}
+
+	return cmd
+}
@@ -1,7 +1,7 @@
 package machine
 
 import (
-	"fmt"
+	"context"
 
 	"connectrpc.com/connect"
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
@@ -13,48 +13,90 @@ import (
 )
 
 func newTerminateCmd() *cobra.Command {
+	var sel machineSelector
+	var concurrency int
+	var yes bool
+
 	cmd := &cobra.Command{
-		Use:     "terminate <id>",
-		Aliases: []string{"stop", "rm"},
+		Use:     "terminate <id>...",
+		Aliases: []string{"rm"},
 		Short:   "Terminate a machine",
 		Example: `# Terminate a machine
 baepo machine terminate ID
 
 # Terminate multiple machines
-baepo machine terminate ID1 ID2`,
+baepo machine terminate ID1 ID2
+
+# Terminate machines whose IDs are read from stdin
+baepo machine terminate -
+
+# Terminate every machine in error state
+baepo machine terminate --all --state error --yes`,
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			a := app.FromContext(ctx)
 
-			if len(args) < 1 {
+			ids, err := resolveMachineIDs(ctx, a, args, sel)
+			if err != nil {
+				return a.APIError(err, baepoerrors.InvalidArgsError, "Selecting machines")
+			}
+
+			if len(ids) == 0 {
+				if sel.All || len(sel.States) > 0 {
+					a.IOStream.Message("No machines found.")
+					return nil
+				}
 				a.IOStream.Error("You must provide at least one machine ID.")
 				return baepoerrors.InvalidArgsError
 			}
 
-			machines := make([]*apiv1pb.Machine, 0)
-			for _, machineID := range args {
-				req := connect.NewRequest(&apiv1pb.MachineTerminateRequest{
-					MachineId: machineID,
-				})
-
-				res, err := a.MachineClient.Terminate(ctx, req)
-				if err != nil {
-					a.IOStream.Error(fmt.Sprintf("Terminating machine %s: %v", machineID, err))
-					continue
+			if a.DryRun {
+				reqs := make([]*apiv1pb.MachineTerminateRequest, len(ids))
+				for i, id := range ids {
+					reqs[i] = &apiv1pb.MachineTerminateRequest{MachineId: id}
 				}
+				a.IOStream.Array(reqs, helper.MachineTerminateRequestMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, %d machine(s) were not terminated.", len(reqs))
+				return nil
+			}
 
-				machines = append(machines, res.Msg.Machine)
+			ok, err := confirmSelection(a.IOStream, sel, "terminated", ids, yes)
+			if err != nil {
+				a.IOStream.Error("Reading confirmation: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+			if !ok {
+				a.IOStream.Message("Termination cancelled, use --yes to terminate the %d selected machines.", len(ids))
+				return baepoerrors.CancelError
 			}
 
-			if len(machines) > 1 {
-				a.IOStream.Array(machines, helper.MachineMapping(), iostream.ObjectOptions{Full: false})
-			} else {
-				a.IOStream.Object(machines[0], helper.MachineMapping(), iostream.ObjectOptions{Full: true})
+			results := runBulk(ctx, ids, concurrency, func(ctx context.Context, id string) error {
+				_, err := a.MachineClient.Terminate(ctx, connect.NewRequest(&apiv1pb.MachineTerminateRequest{
+					MachineId: id,
+				}))
+				return err
+			})
+
+			a.IOStream.Array(results, helper.MachineResultFmtMapping(), iostream.ObjectOptions{Full: false})
+
+			switch failed := countFailed(results); {
+			case failed == len(results):
+				a.IOStream.Error("Failed to terminate %d machine(s).", failed)
+				return baepoerrors.MachineError
+			case failed > 0:
+				a.IOStream.Error("Failed to terminate %d of %d machine(s).", failed, len(results))
+				return baepoerrors.PartialError
 			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&sel.All, "all", false, "Terminate all machines of the workspace")
+	cmd.Flags().StringSliceVar(&sel.States, "state", []string{}, "Only terminate machines in the given state (e.g. error, degraded)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation when --all or --state selects several machines")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultBulkConcurrency, "Maximum number of machines terminated at once")
+
 	return cmd
 }
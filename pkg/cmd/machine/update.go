@@ -0,0 +1,204 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+func newUpdateCmd() *cobra.Command {
+	var name string
+	var sf specFlags
+	var envAdd []string
+	var envRm []string
+	var containerIdx int
+	var yes bool
+	var wait time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Update the spec of a machine",
+		Long: `Update the spec of a machine.
+
+The Baepo API cannot change a machine in place, so the machine is replaced: a
+new machine is created with the updated spec and, once it is running, the old
+one is terminated. If the new machine fails to start, it is terminated and the
+old one is left untouched.`,
+		Example: `# Resize a machine
+baepo machine update ID --cpus 4 --memory 8GiB
+
+# Change the image and add an environment variable to the first container
+baepo machine update ID --image myapp:v2 --env-add LOG_LEVEL=debug
+
+# Remove an environment variable from the second container without confirmation
+baepo machine update ID --container-index 1 --env-rm DEBUG --yes`,
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			if len(args) < 1 {
+				a.IOStream.Error("For machine update, you must provide a machine ID.")
+				return baepoerrors.InvalidArgsError
+			}
+
+			found, err := a.MachineClient.FindById(ctx, connect.NewRequest(&apiv1pb.MachineFindByIdRequest{
+				MachineId: args[0],
+			}))
+			if err != nil {
+				return a.APIError(err, baepoerrors.MachineError, "Inspecting machine")
+			}
+			current := found.Msg.Machine
+
+			spec := proto.Clone(current.GetSpec()).(*corev1pb.MachineSpec)
+			if spec == nil {
+				spec = &corev1pb.MachineSpec{}
+			}
+			if err := sf.applyTo(cmd.Flags(), a.Config, spec, containerIdx); err != nil {
+				a.IOStream.Error("Invalid machine spec: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+			if len(envAdd) > 0 || len(envRm) > 0 {
+				if containerIdx < 0 || containerIdx >= len(spec.Containers) {
+					a.IOStream.Error("Container index %d is out of range, the machine has %d container(s)", containerIdx, len(spec.Containers))
+					return baepoerrors.InvalidArgsError
+				}
+				added, err := parseEnv(envAdd)
+				if err != nil {
+					a.IOStream.Error("Invalid machine spec: %v", err)
+					return baepoerrors.InvalidArgsError
+				}
+
+				container := spec.Containers[containerIdx]
+				if container.Env == nil {
+					container.Env = make(map[string]string)
+				}
+				maps.Copy(container.Env, added)
+				for _, k := range envRm {
+					delete(container.Env, k)
+				}
+			}
+
+			changes := helper.MachineSpecDiff(current.GetSpec(), spec)
+			newName := current.GetName()
+			if cmd.Flags().Changed("name") && name != newName {
+				changes = append([]*helper.SpecChangeFmt{{Field: "name", Current: newName, New: name}}, changes...)
+				newName = name
+			}
+
+			if len(changes) == 0 {
+				a.IOStream.Message("No changes to apply.")
+				return nil
+			}
+
+			// With --json, the changes are part of the single document
+			// printed at the end.
+			if !a.IOStream.JSONOutput {
+				a.IOStream.Array(changes, helper.SpecChangeFmtMapping(), iostream.ObjectOptions{})
+			}
+
+			start := current.GetDesiredState() == corev1pb.MachineDesiredState_MachineDesiredState_Running
+			req := connect.NewRequest(&apiv1pb.MachineCreateRequest{
+				WorkspaceId: current.GetWorkspaceId(),
+				Spec:        spec,
+				Metadata:    current.GetMetadata(),
+				Start:       start,
+			})
+			if newName != "" {
+				req.Msg.Name = &newName
+			}
+
+			if a.DryRun {
+				if a.IOStream.JSONOutput {
+					a.IOStream.Object(&helper.MachineUpdateFmt{Changes: changes, Request: req.Msg}, nil, iostream.ObjectOptions{})
+				} else {
+					a.IOStream.Object(req.Msg, helper.MachineCreateRequestMapping(), iostream.ObjectOptions{Full: true})
+				}
+				a.IOStream.Warning("Dry run, machine %s was not replaced.", current.GetId())
+				return nil
+			}
+
+			if !yes {
+				// JSON output is meant for scripts, which cannot answer.
+				if a.IOStream.JSONOutput && !a.IOStream.CanPrompt() {
+					a.IOStream.Error("Machine %s would be replaced, use --yes to confirm.", current.GetId())
+					return baepoerrors.InvalidArgsError
+				}
+				ok, err := a.IOStream.Confirm("Machine %s will be replaced by a new machine. Continue?", current.GetId())
+				if err != nil {
+					a.IOStream.Error("Reading confirmation: %v", err)
+					return baepoerrors.InvalidArgsError
+				}
+				if !ok {
+					a.IOStream.Message("Update cancelled.")
+					return baepoerrors.CancelError
+				}
+			}
+
+			created, err := a.MachineClient.Create(ctx, req)
+			if err != nil {
+				return a.APIError(err, baepoerrors.MachineError, "Creating replacement machine")
+			}
+			replacement := created.Msg.Machine
+
+			if start {
+				replacement, err = waitForRunning(ctx, a, replacement.GetId(), wait)
+				if err != nil {
+					replacementID := created.Msg.Machine.GetId()
+					a.IOStream.Error("Replacement machine %s did not start: %v.", replacementID, err)
+
+					// The update may have been interrupted, the replacement
+					// must still be cleaned up.
+					_, err = a.MachineClient.Terminate(context.WithoutCancel(ctx), connect.NewRequest(&apiv1pb.MachineTerminateRequest{
+						MachineId: replacementID,
+					}))
+					if err != nil {
+						a.APIError(err, baepoerrors.PartialError, fmt.Sprintf("Terminating replacement machine %s", replacementID))
+						a.IOStream.Error("Machine %s was left untouched, terminate the replacement using the command: baepo machine terminate %s", current.GetId(), replacementID)
+						return baepoerrors.PartialError
+					}
+
+					a.IOStream.Error("Replacement machine %s was terminated, machine %s was left untouched.", replacementID, current.GetId())
+					return baepoerrors.MachineError
+				}
+			}
+
+			_, err = a.MachineClient.Terminate(ctx, connect.NewRequest(&apiv1pb.MachineTerminateRequest{
+				MachineId: current.GetId(),
+			}))
+			if err != nil {
+				a.APIError(err, baepoerrors.PartialError, fmt.Sprintf("Terminating machine %s (replacement machine %s was created)", current.GetId(), replacement.GetId()))
+				return baepoerrors.PartialError
+			}
+
+			if a.IOStream.JSONOutput {
+				a.IOStream.Object(&helper.MachineUpdateFmt{Changes: changes, Machine: replacement}, nil, iostream.ObjectOptions{})
+			} else {
+				a.IOStream.Object(replacement, helper.MachineMapping(), iostream.ObjectOptions{Full: true})
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the machine")
+	sf.register(cmd)
+	cmd.Flags().StringArrayVar(&envAdd, "env-add", []string{}, "Environment variable to add or change, same syntax as --env")
+	cmd.Flags().StringSliceVar(&envRm, "env-rm", []string{}, "Environment variables to remove")
+	cmd.Flags().IntVar(&containerIdx, "container-index", 0, "Index of the container targeted by --image, --env, --command and --health-* flags")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().DurationVar(&wait, "wait", 2*time.Minute, "How long to wait for the replacement machine to be running")
+
+	return cmd
+}
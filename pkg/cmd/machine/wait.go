@@ -0,0 +1,50 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+const waitPollInterval = 2 * time.Second
+
+// waitForRunning polls a machine until it is running. It fails as soon as the
+// machine ends up in error or terminated, or when timeout is reached.
+func waitForRunning(ctx context.Context, a *app.App, machineID string, timeout time.Duration) (*apiv1pb.Machine, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := a.MachineClient.FindById(ctx, connect.NewRequest(&apiv1pb.MachineFindByIdRequest{
+			MachineId: machineID,
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		m := res.Msg.Machine
+		switch m.GetState() {
+		case corev1pb.MachineState_MachineState_Running:
+			return m, nil
+		case corev1pb.MachineState_MachineState_Error,
+			corev1pb.MachineState_MachineState_Terminating,
+			corev1pb.MachineState_MachineState_Terminated:
+			return m, fmt.Errorf("machine is %s", helper.MachineStateToHumanString(m.GetState()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return m, fmt.Errorf("machine is still %s after %s", helper.MachineStateToHumanString(m.GetState()), timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package plugincmd
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newInstallCmd() *cobra.Command {
+	var name string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install <path>",
+		Short: "Install a plugin",
+		Example: `# Install ./baepo-deploy as baepo deploy
+baepo plugin install ./baepo-deploy
+
+# Install a script under another name
+baepo plugin install ./cost.sh --name cost`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			if name == "" {
+				name = plugin.NameFromPath(args[0])
+			}
+			if IsBuiltin(cmd.Root(), name) {
+				a.IOStream.Error("Plugin %q would be shadowed by the built-in command, install it under another name with --name.", name)
+				return baepoerrors.InvalidArgsError
+			}
+
+			p, err := plugin.Install(args[0], name, force)
+			if err != nil {
+				a.IOStream.Error("Failed to install plugin: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+
+			if a.IOStream.JSONOutput {
+				a.IOStream.Object(p, helper.PluginMapping(), iostream.ObjectOptions{})
+				return nil
+			}
+			a.IOStream.Message("Installed plugin %s, run it with: baepo %s", p.Path, p.Name)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&name, "name", "n", "", "Name of the plugin, defaults to the file name without the baepo- prefix and extension")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an installed plugin with the same name")
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package plugincmd
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List plugins",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			plugins, err := plugin.List(BuiltinCommands(cmd.Root()))
+			if err != nil {
+				a.IOStream.Error("Failed to list plugins: %v", err)
+				return baepoerrors.ConfigError
+			}
+
+			if len(plugins) == 0 {
+				a.IOStream.Message("No plugins found.")
+				return nil
+			}
+
+			a.IOStream.Array(plugins, helper.PluginMapping(), iostream.ObjectOptions{})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package plugincmd
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove an installed plugin",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			p, err := plugin.Remove(args[0])
+			if err != nil {
+				a.IOStream.Error("Failed to remove plugin: %v", err)
+				return baepoerrors.InvalidArgsError
+			}
+
+			a.IOStream.Message("Removed plugin %s", p.Path)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,74 @@
+package plugincmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+func NewPluginCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage plugins",
+		Long: `Plugins are executables named baepo-<name>, run as baepo <name>.
+
+They are searched in ~/.baepo/plugins, where baepo plugin install puts them,
+then on PATH. Built-in commands always take precedence over plugins.
+
+Plugins get the arguments following their name as is, and the resolved
+context in the BAEPO_CONTEXT, BAEPO_URL, BAEPO_SECRET_KEY, BAEPO_USER_ID and
+BAEPO_WORKSPACE_ID environment variables. BAEPO_BIN is the path of the CLI,
+for plugins calling it back.
+
+The global flags given before the name of a plugin apply to it: --context
+selects the exported context, --workspace overrides BAEPO_WORKSPACE_ID and
+--json sets BAEPO_JSON=1. Flags given after the name are passed to the plugin.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newInstallCmd())
+	cmd.AddCommand(newRemoveCmd())
+
+	return cmd
+}
+
+// Lookup returns the plugin to run for args, when their first element is not
+// a built-in command of root, and the arguments to pass to it.
+func Lookup(root *cobra.Command, args []string) (*plugin.Plugin, []string, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") ||
+		args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd {
+		return nil, nil, false
+	}
+
+	if IsBuiltin(root, args[0]) {
+		return nil, nil, false
+	}
+
+	p, ok := plugin.Find(args[0])
+	if !ok {
+		return nil, nil, false
+	}
+	return p, args[1:], true
+}
+
+// BuiltinCommands returns the names and aliases of the commands of root.
+func BuiltinCommands(root *cobra.Command) []string {
+	// The help and completion commands are only added by Execute.
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	var names []string
+	for _, c := range root.Commands() {
+		names = append(names, c.Name())
+		names = append(names, c.Aliases...)
+	}
+	return names
+}
+
+// IsBuiltin reports whether name is a command, or an alias of a command, of
+// root.
+func IsBuiltin(root *cobra.Command, name string) bool {
+	return slices.Contains(BuiltinCommands(root), name)
+}
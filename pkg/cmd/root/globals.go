@@ -0,0 +1,70 @@
+package root
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// GlobalFlags are the persistent flags of the root command given before the
+// command name, e.g. -x prod in baepo -x prod deploy. Aliases and plugins are
+// resolved before cobra parses any flag, these are read beforehand so that
+// they apply to them too.
+type GlobalFlags struct {
+	Context   string
+	Workspace string
+	JSON      bool
+
+	// Args are the flags as given, to pass them on to cobra.
+	Args []string
+}
+
+// SplitGlobalFlags splits the persistent flags of root leading args from the
+// command and its arguments. It stops at the first argument that is not a
+// persistent flag of root, which cobra reports if it is invalid.
+func SplitGlobalFlags(root *cobra.Command, args []string) (*GlobalFlags, []string) {
+	g := &GlobalFlags{}
+
+	n := 0
+	for n < len(args) {
+		arg := args[n]
+		if arg == "-" || arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		var flag *pflag.Flag
+		var inline bool
+		if name, ok := strings.CutPrefix(arg, "--"); ok {
+			name, _, inline = strings.Cut(name, "=")
+			flag = root.PersistentFlags().Lookup(name)
+		} else {
+			flag = root.PersistentFlags().ShorthandLookup(arg[1:2])
+			inline = len(arg) > 2
+		}
+		if flag == nil {
+			break
+		}
+
+		if inline || flag.NoOptDefVal != "" {
+			n++
+		} else if n+1 < len(args) {
+			n += 2
+		} else {
+			break
+		}
+	}
+	g.Args = args[:n]
+
+	fs := pflag.NewFlagSet("global", pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.StringVarP(&g.Context, "context", "x", "default", "")
+	fs.StringVar(&g.Workspace, "workspace", "", "")
+	fs.BoolVarP(&g.JSON, "json", "j", false, "")
+	if err := fs.Parse(g.Args); err != nil {
+		// Invalid values are left to cobra, which reports them.
+		return &GlobalFlags{Context: "default"}, args
+	}
+
+	return g, args[n:]
+}
@@ -0,0 +1,35 @@
+package root_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/root"
+)
+
+func TestSplitGlobalFlags(t *testing.T) {
+	for _, tc := range []struct {
+		args      []string
+		context   string
+		workspace string
+		json      bool
+		rest      []string
+	}{
+		{[]string{"deploy", "-x", "prod"}, "default", "", false, []string{"deploy", "-x", "prod"}},
+		{[]string{"-x", "prod", "deploy", "--json"}, "prod", "", false, []string{"deploy", "--json"}},
+		{[]string{"--context=prod", "-j", "--workspace", "w2", "ml"}, "prod", "w2", true, []string{"ml"}},
+		{[]string{"-xprod", "--debug", "--timeout", "5s", "ml", "ID"}, "prod", "", false, []string{"ml", "ID"}},
+		{[]string{"--json", "--", "ml"}, "default", "", true, []string{"--", "ml"}},
+		{[]string{"--unknown", "ml"}, "default", "", false, []string{"--unknown", "ml"}},
+		{[]string{"-x"}, "default", "", false, []string{"-x"}},
+		{[]string{"--json=maybe", "ml"}, "default", "", false, []string{"--json=maybe", "ml"}},
+	} {
+		globals, rest := root.SplitGlobalFlags(root.NewCmdRoot(), tc.args)
+		if globals.Context != tc.context || globals.Workspace != tc.workspace || globals.JSON != tc.json {
+			t.Errorf("%q: expected context %q, workspace %q and json %v, got %+v", tc.args, tc.context, tc.workspace, tc.json, globals)
+		}
+		if !slices.Equal(rest, tc.rest) {
+			t.Errorf("%q: expected %q to be left, got %q", tc.args, tc.rest, rest)
+		}
+	}
+}
@@ -1,15 +1,23 @@
 package root
 
 import (
+	"io"
+	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"connectrpc.com/connect"
 	"github.com/MakeNowJust/heredoc"
 	"github.com/baepo-cloud/baepo-cli/pkg/app"
 	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/aliascmd"
 	"github.com/baepo-cloud/baepo-cli/pkg/cmd/auth"
 	"github.com/baepo-cloud/baepo-cli/pkg/cmd/contextcmd"
 	"github.com/baepo-cloud/baepo-cli/pkg/cmd/machine"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/plugincmd"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/workspace"
 	"github.com/baepo-cloud/baepo-cli/pkg/config"
 	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
 	"github.com/spf13/cobra"
@@ -18,23 +26,50 @@ import (
 var (
 	rootFlagCurrentContext = "default"
 	rootJSONOutput         = false
+	rootDryRun             = false
+	rootDebug              = false
+	rootTraceFile          = ""
+	rootTimeout            time.Duration
+	rootWorkspace          = ""
 )
 
 func NewCmdRoot() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:           "baepo <command> <subcommand> [flags]",
-		Short:         "Baepo CLI",
-		Long:          `Work seamlessly with Baepo from the command line.`,
+		Use:   "baepo <command> <subcommand> [flags]",
+		Short: "Baepo CLI",
+		Long: heredoc.Doc(`
+			Work seamlessly with Baepo from the command line.
+
+			Exit codes:
+			  0   Success
+			  1   Unexpected error
+			  2   Cancelled, e.g. a confirmation was refused
+			  3   Invalid arguments, flags or spec
+			  4   Not authenticated
+			  5   Permission denied
+			  6   Not found
+			  7   API unavailable or rate limited
+			  9   API call timed out
+			  16  Partial failure, some machines of a bulk operation failed
+
+			Environment:
+			  BAEPO_CONTEXT  Context to use instead of the current one
+			  BAEPO_TOKEN    API token used instead of the credentials of the context, e.g. in CI
+			  BAEPO_DEBUG    Trace API calls to stderr when set to 1
+
+			With --json, errors are written to stderr as {"error": "...", "code": "..."},
+			code being the API error code (e.g. not_found) when the API returned one.
+		`),
 		SilenceErrors: true,
 		Example: heredoc.Doc(`
 			$ baepo auth login --email lou@corp.com --password corp123Corp
 			$ baepo machine create \
 			  --name web-server \
-			  --vcpus 2 \
-			  --memory-mb 4096 \
+			  --cpus 2 \
+			  --memory 4096 \
 			  --image ubuntu:latest \
 			  --env "NODE_ENV=production" --env "PORT=3000" \
-			  --command "/usr/bin/startup.sh" 
+			  --command "/usr/bin/startup.sh"
 			$ baepo machine ls
 		`),
 		Annotations: map[string]string{
@@ -45,19 +80,65 @@ func NewCmdRoot() *cobra.Command {
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			ios := iostream.New(rootJSONOutput)
 			cfg, err := config.LoadConfig(rootFlagCurrentContext)
-
-			a := app.NewApp(cfg, ios)
-			cmd.SetContext(app.SaveToContext(a, cmd.Context()))
-
 			if err != nil {
-				a.IOStream.Error("failed to load config: %v", err)
+				ios.Error("failed to load config: %v", err)
 				return baepoerrors.ConfigError
 			}
 
 			p := getFirstSubcommand(cmd)
 
-			if cfg.CurrentContext.SecretKey == "" && !slices.Contains([]string{"auth", "context"}, p) {
-				a.IOStream.Error("No secret key found in the current context. Please login to Baepo using the command: baepo auth login --email <email> --password <password>")
+			// Commands managing local files do not call the API. Invalid
+			// settings of the current context are only a warning for them,
+			// so that they can be used to fix the settings.
+			local := slices.Contains([]string{"alias", "context", "plugin"}, p)
+			report := ios.Error
+			if local {
+				report = ios.Warning
+			}
+
+			opts, trace, err := clientOptions(cmd, cfg, ios, report)
+			if err != nil && !local {
+				return err
+			}
+
+			var httpClient connect.HTTPClient
+			httpClient, err = app.NewHTTPClient(cfg.CurrentContext)
+			if err != nil {
+				report("Invalid transport settings in the current context: %v", err)
+				if !local {
+					if trace != nil {
+						trace.Close()
+					}
+					return baepoerrors.ConfigError
+				}
+				httpClient = app.FailingHTTPClient(err)
+			}
+			if cfg.CurrentContext.InsecureSkipVerify {
+				ios.Warning("TLS certificate verification is disabled for the current context, connections to %s can be intercepted.", cfg.CurrentContext.URL)
+			}
+
+			a := app.NewApp(cfg, ios, httpClient, opts...)
+			a.DryRun = rootDryRun
+			a.Workspace = rootWorkspace
+			a.Trace = trace
+			cmd.SetContext(app.SaveToContext(a, cmd.Context()))
+
+			if rootDryRun && cmd.Annotations[app.DryRunAnnotation] == "" {
+				a.IOStream.Error("%s does not support --dry-run.", cmd.CommandPath())
+				return baepoerrors.InvalidArgsError
+			}
+
+			if local || p == "auth" {
+				return nil
+			}
+
+			c := cfg.CurrentContext
+			if c.SecretKey == "" && c.Token == "" && c.AccessToken == "" {
+				a.IOStream.Error("No secret key found in the current context. Please login to Baepo using the command: baepo auth login --email <email> --password <password> or baepo auth login --web, or set BAEPO_TOKEN.")
+				return baepoerrors.AuthError
+			}
+			if c.Token == "" && c.AccessToken != "" && !c.AccessTokenExpiresAt.IsZero() && time.Now().After(c.AccessTokenExpiresAt) {
+				a.IOStream.Error("The session of the current context expired on %s. Please login again using the command: baepo auth login --web", c.AccessTokenExpiresAt.Local().Format(time.DateTime))
 				return baepoerrors.AuthError
 			}
 			return nil
@@ -69,15 +150,85 @@ func NewCmdRoot() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&rootFlagCurrentContext, "context", "x", "default", "Set the current context")
+	cmd.PersistentFlags().StringVar(&rootWorkspace, "workspace", "", "Act on this workspace instead of the one of the current context")
 	cmd.PersistentFlags().BoolVarP(&rootJSONOutput, "json", "j", false, "Output in JSON format")
+	cmd.PersistentFlags().DurationVar(&rootTimeout, "timeout", app.DefaultTimeout, "Timeout of each API call, overrides the timeout of the context")
+	cmd.PersistentFlags().BoolVar(&rootDebug, "debug", false, "Trace API calls to stderr, also enabled by BAEPO_DEBUG=1")
+	cmd.PersistentFlags().StringVar(&rootTraceFile, "trace-file", "", "Trace API calls to this file instead of stderr")
+	cmd.PersistentFlags().BoolVar(&rootDryRun, "dry-run", false, "Print the requests and config changes a command would make without applying them")
 
 	cmd.AddCommand(contextcmd.NewContextCmd())
 	cmd.AddCommand(auth.NewAuthCmd())
 	cmd.AddCommand(machine.NewMachineCmd())
+	cmd.AddCommand(plugincmd.NewPluginCmd())
+	cmd.AddCommand(aliascmd.NewAliasCmd())
+	cmd.AddCommand(workspace.NewWorkspaceCmd())
 
 	return cmd
 }
 
+// clientOptions returns the options shared by every service client: protocol
+// settings, then retries, a timeout for each attempt and tracing, in this order
+// so that each attempt is traced.
+//
+// The trace writer is returned as well, to be closed once the command has
+// run. Invalid settings are reported with report.
+func clientOptions(cmd *cobra.Command, cfg *config.Config, ios *iostream.IOStream, report func(string, ...interface{})) ([]connect.ClientOption, io.WriteCloser, error) {
+	timeout := rootTimeout
+	if !cmd.Flags().Changed("timeout") && cfg.CurrentContext.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.CurrentContext.Timeout)
+		if err != nil {
+			report("Invalid timeout %q in the current context: %v", cfg.CurrentContext.Timeout, err)
+			return nil, nil, baepoerrors.ConfigError
+		}
+	}
+
+	opts, err := app.ProtocolClientOptions(cfg.CurrentContext)
+	if err != nil {
+		report("Invalid protocol settings in the current context: %v", err)
+		return nil, nil, baepoerrors.ConfigError
+	}
+
+	opts = append(opts,
+		app.RetryClientOption(app.DefaultRetryPolicy),
+		app.TimeoutClientOption(timeout),
+	)
+
+	trace, err := traceWriter(ios)
+	if err != nil {
+		report("Opening trace file: %v", err)
+		return nil, nil, baepoerrors.ConfigError
+	}
+	if trace != nil {
+		opts = append(opts, app.DebugClientOption(trace))
+	}
+
+	return opts, trace, nil
+}
+
+// traceWriter returns where API calls are traced, or nil when tracing is off.
+// Closing it only closes the trace file, never stderr.
+func traceWriter(ios *iostream.IOStream) (io.WriteCloser, error) {
+	if rootTraceFile != "" {
+		f, err := os.OpenFile(rootTraceFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if debug, _ := strconv.ParseBool(os.Getenv("BAEPO_DEBUG")); rootDebug || debug {
+		return nopCloser{ios.Stderr}, nil
+	}
+	return nil, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
 func getFirstSubcommand(cmd *cobra.Command) string {
 	// Walk up the command chain to find the root command
 	root := cmd
@@ -0,0 +1,351 @@
+package root_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/cmd/root"
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TestExamplesParse checks that every command line given in an Example string
+// refers to an existing command, only uses flags that exist and passes valid
+// arguments.
+func TestExamplesParse(t *testing.T) {
+	for _, cmd := range allCommands(root.NewCmdRoot()) {
+		for _, example := range exampleCommandLines(cmd.Example) {
+			t.Run(example, func(t *testing.T) {
+				words, err := shellwords.Split(example)
+				if err != nil {
+					t.Fatalf("Splitting example of %q: %v", cmd.CommandPath(), err)
+				}
+
+				// Build a fresh tree so that flags parsed by an example do not
+				// leak into the next one.
+				target, args, err := root.NewCmdRoot().Find(words[1:])
+				if err != nil {
+					t.Fatalf("Example of %q: %v", cmd.CommandPath(), err)
+				}
+				if err := target.ParseFlags(args); err != nil {
+					t.Fatalf("Example of %q: %v", cmd.CommandPath(), err)
+				}
+				// Find stops at the last known command, a misspelled
+				// subcommand is left in the arguments.
+				if err := target.ValidateArgs(target.Flags().Args()); err != nil {
+					t.Fatalf("Example of %q: %v", cmd.CommandPath(), err)
+				}
+				if target.HasSubCommands() && target.Flags().NArg() > 0 {
+					t.Fatalf("Example of %q: unknown command %q for %q", cmd.CommandPath(), target.Flags().Arg(0), target.CommandPath())
+				}
+			})
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cmd := root.NewCmdRoot()
+	cmd.SetArgs([]string{"context", "create", "staging", "--url", "https://staging.example.com/", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".baepo", "config.yaml")); !os.IsNotExist(err) {
+		t.Errorf("Expected no config file to be created, got %v", err)
+	}
+
+	cmd = root.NewCmdRoot()
+	cmd.SetArgs([]string{"auth", "login", "--email", "a@b.c", "--password", "x", "--dry-run"})
+	if err := cmd.Execute(); !errors.Is(err, baepoerrors.InvalidArgsError) {
+		t.Errorf("Expected --dry-run to be rejected by auth login, got %v", err)
+	}
+}
+
+type workspaceServer struct {
+	apiv1pbconnect.UnimplementedMachineServiceHandler
+	workspaceID string
+}
+
+func (s *workspaceServer) List(ctx context.Context, req *connect.Request[apiv1pb.MachineListRequest]) (*connect.Response[apiv1pb.MachineListResponse], error) {
+	s.workspaceID = req.Msg.WorkspaceId
+	return connect.NewResponse(&apiv1pb.MachineListResponse{}), nil
+}
+
+func TestWorkspaceOverride(t *testing.T) {
+	srv := &workspaceServer{}
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewMachineServiceHandler(srv))
+	httpSrv := httptest.NewServer(mux)
+	defer httpSrv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("BAEPO_URL", httpSrv.URL)
+	t.Setenv("BAEPO_SECRET_KEY", "sk")
+	t.Setenv("BAEPO_WORKSPACE_ID", "w1")
+
+	cmd := root.NewCmdRoot()
+	cmd.SetArgs([]string{"machine", "list", "--workspace", "w2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if srv.workspaceID != "w2" {
+		t.Errorf("Expected machines of workspace w2 to be listed, got %q", srv.workspaceID)
+	}
+
+	cmd = root.NewCmdRoot()
+	cmd.SetArgs([]string{"machine", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if srv.workspaceID != "w1" {
+		t.Errorf("Expected the workspace of the context once --workspace is gone, got %q", srv.workspaceID)
+	}
+}
+
+type inspectServer struct {
+	apiv1pbconnect.UnimplementedMachineServiceHandler
+	apiv1pbconnect.UnimplementedUserServiceHandler
+}
+
+func (inspectServer) Me(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[apiv1pb.UserMeResponse], error) {
+	return connect.NewResponse(&apiv1pb.UserMeResponse{User: &apiv1pb.User{Id: "u1", WorkspaceId: "w1"}}), nil
+}
+
+func (inspectServer) List(_ context.Context, req *connect.Request[apiv1pb.MachineListRequest]) (*connect.Response[apiv1pb.MachineListResponse], error) {
+	if req.Msg.WorkspaceId != "w2" {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("workspace not found"))
+	}
+	return connect.NewResponse(&apiv1pb.MachineListResponse{}), nil
+}
+
+func TestWorkspaceInspect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewMachineServiceHandler(inspectServer{}))
+	mux.Handle(apiv1pbconnect.NewUserServiceHandler(inspectServer{}))
+	httpSrv := httptest.NewServer(mux)
+	defer httpSrv.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BAEPO_URL", httpSrv.URL)
+	t.Setenv("BAEPO_SECRET_KEY", "sk")
+
+	for id, expected := range map[string]error{
+		"w1":   nil,
+		"w2":   nil,
+		"nope": baepoerrors.NotFoundError,
+	} {
+		cmd := root.NewCmdRoot()
+		cmd.SetArgs([]string{"workspace", "inspect", id})
+		if err := cmd.Execute(); !errors.Is(err, expected) {
+			t.Errorf("%s: expected %v, got %v", id, expected, err)
+		}
+	}
+
+	// The workspace of the user is accepted without listing its machines.
+	for id, expected := range map[string]error{
+		"w1":   nil,
+		"w2":   nil,
+		"nope": baepoerrors.NotFoundError,
+	} {
+		cmd := root.NewCmdRoot()
+		cmd.SetArgs([]string{"workspace", "use", id, "--dry-run"})
+		if err := cmd.Execute(); !errors.Is(err, expected) {
+			t.Errorf("use %s: expected %v, got %v", id, expected, err)
+		}
+	}
+}
+
+type webLoginUserServer struct {
+	apiv1pbconnect.UnimplementedUserServiceHandler
+}
+
+func (webLoginUserServer) Me(_ context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[apiv1pb.UserMeResponse], error) {
+	if req.Header().Get("Authorization") != "Bearer at-1" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	return connect.NewResponse(&apiv1pb.UserMeResponse{User: &apiv1pb.User{Id: "u1", FirstName: "Lou", WorkspaceId: "w1"}}), nil
+}
+
+// TestWebLogin runs auth login --web against a fake API that is its own
+// authorization server, approving the login on the first poll.
+func TestWebLogin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(apiv1pbconnect.NewUserServiceHandler(webLoginUserServer{}))
+
+	writeJSON := func(w http.ResponseWriter, status int, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+	polls := 0
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, fmt.Sprintf(`{"device_authorization_endpoint":"http://%[1]s/device","token_endpoint":"http://%[1]s/token"}`, r.Host))
+	})
+	mux.HandleFunc("POST /device", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"device_code":"dc-1","user_code":"ABCD-EFGH","verification_uri":"https://sso.example.com/activate","expires_in":60,"interval":1}`)
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		writeJSON(w, http.StatusOK, `{"access_token":"at-1","token_type":"Bearer","expires_in":3600}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("BAEPO_URL", srv.URL)
+
+	cmd := root.NewCmdRoot()
+	cmd.SetArgs([]string{"auth", "login", "--web"})
+	if err := cmd.Execute(); !errors.Is(err, baepoerrors.ConfigError) {
+		t.Fatalf("Expected a context without OAuth issuer to be rejected, got %v", err)
+	}
+	if polls != 0 {
+		t.Fatalf("Expected no poll without OAuth issuer, got %d", polls)
+	}
+
+	configPath := filepath.Join(home, ".baepo", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	initial := fmt.Sprintf("version: \"0.1\"\ncontext: default\ncontexts:\n  default:\n    oauth_issuer: %s\n", srv.URL)
+	if err := os.WriteFile(configPath, []byte(initial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd = root.NewCmdRoot()
+	cmd.SetArgs([]string{"auth", "login", "--web"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Reading config: %v", err)
+	}
+	if info, err := os.Stat(configPath); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the config holding the access token to be private, got %v", info.Mode().Perm())
+	}
+	if polls != 1 {
+		t.Errorf("Expected a single poll, got %d", polls)
+	}
+	for _, expected := range []string{"access_token: at-1", "access_token_expires_at:", "user_id: u1", "workspace_id: w1"} {
+		if !strings.Contains(string(cfg), expected) {
+			t.Errorf("Expected the config to contain %q, got:\n%s", expected, cfg)
+		}
+	}
+}
+
+func TestExpiredAccessToken(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".baepo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `version: "0.1"
+context: default
+contexts:
+  default:
+    url: http://127.0.0.1:1/
+    access_token: at-1
+    access_token_expires_at: 2020-01-01T00:00:00Z
+`
+	if err := os.WriteFile(filepath.Join(home, ".baepo", "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := root.NewCmdRoot()
+	cmd.SetArgs([]string{"machine", "list"})
+	if err := cmd.Execute(); !errors.Is(err, baepoerrors.AuthError) {
+		t.Errorf("Expected an expired session to be reported as an auth error, got %v", err)
+	}
+}
+
+func TestInvalidTransportSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".baepo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `version: "0.1"
+context: default
+contexts:
+  default:
+    url: https://127.0.0.1:1/
+    secret_key: sk
+    user_id: u1
+    ca_file: /nonexistent.pem
+`
+	if err := os.WriteFile(filepath.Join(home, ".baepo", "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := root.NewCmdRoot()
+	cmd.SetArgs([]string{"context", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Expected context commands to work with invalid transport settings, got %v", err)
+	}
+
+	cmd = root.NewCmdRoot()
+	cmd.SetArgs([]string{"machine", "list"})
+	if err := cmd.Execute(); !errors.Is(err, baepoerrors.ConfigError) {
+		t.Errorf("Expected API commands to fail with invalid transport settings, got %v", err)
+	}
+}
+
+func allCommands(cmd *cobra.Command) []*cobra.Command {
+	cmds := []*cobra.Command{cmd}
+	for _, sub := range cmd.Commands() {
+		cmds = append(cmds, allCommands(sub)...)
+	}
+	return cmds
+}
+
+// exampleCommandLines extracts the baepo invocations of an Example string,
+// joining lines continued with a trailing backslash and skipping comments.
+func exampleCommandLines(example string) []string {
+	var lines []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(example, "\n") {
+		line = strings.TrimSpace(line)
+		if current.Len() == 0 {
+			line = strings.TrimPrefix(line, "$ ")
+			if !strings.HasPrefix(line, "baepo ") {
+				continue
+			}
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+
+		current.WriteString(line)
+		lines = append(lines, current.String())
+		current.Reset()
+	}
+
+	return lines
+}
@@ -0,0 +1,67 @@
+package workspace
+
+import (
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/spf13/cobra"
+)
+
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect [id]",
+		Short: "Show a workspace, the current one by default",
+		Long: `Show a workspace, the current one by default.
+
+Workspaces other than the one of your user are checked by listing their
+machines, which is best-effort: the API may list no machines for a workspace
+that does not exist instead of failing.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			id := a.WorkspaceID()
+			if len(args) > 0 {
+				id = args[0]
+			}
+			if id == "" {
+				a.IOStream.Error("No workspace in the current context, select one with: baepo workspace use <id>")
+				return baepoerrors.InvalidArgsError
+			}
+
+			list, err := knownWorkspaces(ctx, a)
+			if err != nil {
+				return a.APIError(err, baepoerrors.AuthError, "Failed to get user info")
+			}
+
+			w := &helper.WorkspaceFmt{ID: id}
+			for _, known := range list {
+				if known.ID == id {
+					w = known
+				}
+			}
+
+			// Workspaces other than the one of the user may not exist or be
+			// accessible. Listing their machines fails in that case, but the
+			// server may as well return an empty list.
+			if !w.User {
+				_, err := a.MachineClient.List(ctx, connect.NewRequest(&apiv1pb.MachineListRequest{
+					WorkspaceId: id,
+				}))
+				if err != nil {
+					return a.APIError(err, baepoerrors.NotFoundError, "Cannot inspect workspace "+id)
+				}
+			}
+
+			a.IOStream.Object(w, helper.WorkspaceFmtMapping(), iostream.ObjectOptions{Full: true})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,37 @@
+package workspace
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List known workspaces",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			list, err := knownWorkspaces(ctx, a)
+			if err != nil {
+				return a.APIError(err, baepoerrors.AuthError, "Failed to get user info")
+			}
+
+			if len(list) == 0 {
+				a.IOStream.Message("No workspaces found.")
+				return nil
+			}
+
+			a.IOStream.Array(list, helper.WorkspaceFmtMapping(), iostream.ObjectOptions{})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
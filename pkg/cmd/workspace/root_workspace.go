@@ -0,0 +1,82 @@
+package workspace
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func NewWorkspaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Manage your workspaces",
+		Long: `Manage the workspace of the current context.
+
+The API only tells the workspace of your user, so the known workspaces are
+that one and the ones of your contexts. Whether you can access any other
+workspace is checked on a best-effort basis. Use --workspace to run a single
+command in another workspace.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newUseCmd())
+	cmd.AddCommand(newInspectCmd())
+
+	return cmd
+}
+
+// knownWorkspaces returns the workspace of the user first, then the ones of
+// the contexts and of --workspace sorted by ID.
+func knownWorkspaces(ctx context.Context, a *app.App) ([]*helper.WorkspaceFmt, error) {
+	me, err := a.UserClient.Me(ctx, connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		return nil, err
+	}
+
+	current := a.WorkspaceID()
+	userWorkspace := me.Msg.GetUser().GetWorkspaceId()
+
+	workspaces := map[string]*helper.WorkspaceFmt{}
+	add := func(id string) *helper.WorkspaceFmt {
+		w, ok := workspaces[id]
+		if !ok {
+			w = &helper.WorkspaceFmt{
+				ID:      id,
+				Current: id == current,
+				User:    id == userWorkspace,
+			}
+			workspaces[id] = w
+		}
+		return w
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(a.Config.Contexts)) {
+		if id := a.Config.Contexts[name].WorkspaceID; id != "" {
+			w := add(id)
+			w.Contexts = append(w.Contexts, name)
+		}
+	}
+	for _, id := range []string{userWorkspace, current} {
+		if id != "" {
+			add(id)
+		}
+	}
+
+	list := slices.SortedFunc(maps.Values(workspaces), func(x, y *helper.WorkspaceFmt) int {
+		if x.User != y.User {
+			if x.User {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x.ID, y.ID)
+	})
+	return list, nil
+}
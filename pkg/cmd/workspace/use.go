@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/app"
+	"github.com/baepo-cloud/baepo-cli/pkg/baepoerrors"
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func newUseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use <id>",
+		Short: "Use a workspace in the current context",
+		Long: `Use a workspace in the current context.
+
+The workspace of your user is always accepted. Other workspaces are checked by
+listing their machines, which is best-effort: the API has no way to tell
+whether you are a member of a workspace, and may list no machines for a
+workspace that does not exist instead of failing.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{app.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			a := app.FromContext(ctx)
+
+			id := args[0]
+
+			me, err := a.UserClient.Me(ctx, connect.NewRequest(&emptypb.Empty{}))
+			if err != nil {
+				return a.APIError(err, baepoerrors.AuthError, "Failed to get user info")
+			}
+
+			// The API only tells the workspace of the user. Listing the
+			// machines of any other one fails when it cannot be accessed,
+			// but the server may as well return an empty list.
+			if id != me.Msg.GetUser().GetWorkspaceId() {
+				_, err = a.MachineClient.List(ctx, connect.NewRequest(&apiv1pb.MachineListRequest{
+					WorkspaceId: id,
+				}))
+				if err != nil {
+					return a.APIError(err, baepoerrors.InvalidArgsError, "Cannot use workspace "+id)
+				}
+			}
+
+			a.Config.CurrentContext.WorkspaceID = id
+
+			if a.DryRun {
+				a.IOStream.Object(&helper.ContextFmt{Name: a.Config.Context, Current: true, Value: *a.Config.CurrentContext}, helper.ContextFmtMapping(), iostream.ObjectOptions{})
+				a.IOStream.Warning("Dry run, the config was not saved.")
+				return nil
+			}
+
+			err = config.SaveConfig(a.Config)
+			if err != nil {
+				a.IOStream.Error("Failed to save config: %v", err)
+				return baepoerrors.ConfigError
+			}
+
+			a.IOStream.Message("Switched context '%s' to workspace '%s'", a.Config.Context, id)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
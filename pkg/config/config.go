@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"gopkg.in/yaml.v3"
@@ -14,6 +15,15 @@ type Config struct {
 	Context  string              `yaml:"context" env:"BAEPO_CONTEXT"`
 
 	CurrentContext *Context `yaml:"-"` // Not saved to config file
+	// CurrentContextName is the name of CurrentContext, which can differ from
+	// Context when another one is selected with --context.
+	CurrentContextName string `yaml:"-"`
+
+	// Sizes are user defined machine size presets, usable with --size.
+	Sizes map[string]*Size `yaml:"sizes,omitempty"`
+
+	// Aliases are user defined commands, expanded before running the CLI.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 
 	ConfigVersion string `yaml:"version"`
 }
@@ -23,6 +33,55 @@ type Context struct {
 	WorkspaceID string `yaml:"workspace_id" env:"BAEPO_WORKSPACE_ID" env-upd:""`
 	UserID      string `yaml:"user_id" env:"BAEPO_USER_ID" env-upd:""`
 	URL         string `yaml:"url" env:"BAEPO_URL" env-upd:""`
+
+	// Token is an API token sent as is, instead of the user ID and secret key.
+	// It is only read from the environment, for CI jobs, and never saved or
+	// printed.
+	Token string `yaml:"-" json:"-" env:"BAEPO_TOKEN" env-upd:""`
+	// AccessToken is the token obtained with auth login --web, sent instead
+	// of the user ID and secret key. It is never printed.
+	AccessToken string `yaml:"access_token,omitempty" json:"-"`
+	// AccessTokenExpiresAt is when the access token expires, zero when
+	// unknown.
+	AccessTokenExpiresAt time.Time `yaml:"access_token_expires_at,omitempty"`
+
+	// Timeout bounds each API call, e.g. "30s" or "2m". It can be overridden
+	// with --timeout.
+	Timeout string `yaml:"timeout,omitempty" env:"BAEPO_TIMEOUT" env-upd:""`
+
+	// CAFile is a PEM bundle trusted in addition to the system CAs.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// ClientCert and ClientKey are a PEM key pair used for mutual TLS.
+	ClientCert string `yaml:"client_cert,omitempty"`
+	ClientKey  string `yaml:"client_key,omitempty"`
+	// InsecureSkipVerify disables TLS certificate verification.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify,omitempty"`
+	// Proxy is the URL of the proxy to reach the API through. The proxy
+	// environment variables are used when it is empty.
+	Proxy string `yaml:"proxy,omitempty"`
+	// Headers are added to every request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Protocol is connect (default), grpc or grpcweb.
+	Protocol string `yaml:"protocol,omitempty"`
+	// Encoding is proto (default) or json.
+	Encoding string `yaml:"encoding,omitempty"`
+	// Compression of requests and responses, gzip or none. By default
+	// requests are not compressed and gzip responses are accepted.
+	Compression string `yaml:"compression,omitempty"`
+
+	// OAuthIssuer is the authorization server of auth login --web, which
+	// requires it.
+	OAuthIssuer string `yaml:"oauth_issuer,omitempty"`
+	// OAuthClientID is the client ID of the CLI at the authorization server,
+	// baepo-cli by default.
+	OAuthClientID string `yaml:"oauth_client_id,omitempty"`
+}
+
+// Size is a machine size preset. Memory accepts units, e.g. "512MiB" or "2GiB".
+type Size struct {
+	Cpus   uint32 `yaml:"cpus"`
+	Memory string `yaml:"memory"`
 }
 
 var DefaultContext = &Context{
@@ -34,7 +93,8 @@ var DefaultContext = &Context{
 
 // LoadConfig loads the configuration from the specified file path.
 // First it tries to load from the file in $HOME/.baepo/config.yaml
-// If that file does not exist, it will create it and fill with default values.
+// If that file does not exist, the default values are used. The file is only
+// created by SaveConfig, loading the configuration never writes it.
 // If the file exists, it will load the configuration from it.
 //
 // Then it will check if the current context is set.
@@ -51,15 +111,7 @@ func LoadConfig(currentContext string) (*Config, error) {
 		return nil, fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	configDir := path.Join(homeDir, ".baepo")
-	configPath := path.Join(configDir, "config.yaml")
-
-	// Ensure config directory exists
-	if _, err := os.Stat(configDir); os.IsNotExist(err) {
-		if err := os.MkdirAll(configDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create config directory: %w", err)
-		}
-	}
+	configPath := path.Join(homeDir, ".baepo", "config.yaml")
 
 	// Initialize with default values
 	configuration = &Config{
@@ -70,15 +122,12 @@ func LoadConfig(currentContext string) (*Config, error) {
 		ConfigVersion: "0.1",
 	}
 
-	// Create config file with defaults if it doesn't exist
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		if err := SaveConfig(configuration); err != nil {
-			return nil, fmt.Errorf("failed to save default config: %w", err)
+	// Read configuration file, the defaults are kept if it doesn't exist
+	if _, err := os.Stat(configPath); err == nil {
+		if err := cleanenv.ReadConfig(configPath, configuration); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
-	}
-
-	// Read configuration file (cleanenv will use default values from the struct if file doesn't exist)
-	if err := cleanenv.ReadConfig(configPath, configuration); err != nil {
+	} else if !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
@@ -103,6 +152,7 @@ func LoadConfig(currentContext string) (*Config, error) {
 
 	// Set the current context
 	configuration.CurrentContext = selectedContext
+	configuration.CurrentContextName = contextName
 
 	err = cleanenv.UpdateEnv(configuration.CurrentContext)
 	if err != nil {
@@ -132,9 +182,24 @@ func SaveConfig(cfg *Config) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal config to YAML: %w", err)
 	}
-	if err := os.WriteFile(configPath, out, 0644); err != nil {
+	// The config holds secret keys and access tokens. WriteFile only sets
+	// the permissions of new files, older ones are restricted too.
+	if err := os.WriteFile(configPath, out, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
+	if err := os.Chmod(configPath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict config file permissions: %w", err)
+	}
 
 	return nil
 }
+
+// Dir returns the directory holding the config file and the files managed by
+// the CLI, $HOME/.baepo.
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return path.Join(homeDir, ".baepo"), nil
+}
@@ -0,0 +1,252 @@
+// Package dotenv parses environment files in the dotenv format.
+//
+// The supported syntax is:
+//
+//	# comments and blank lines are ignored
+//	export KEY=value        # "export" prefixes are allowed, trailing comments too
+//	KEY='literal $value'    # single quotes: no escapes, no interpolation
+//	KEY="line 1\nline 2"    # double quotes: escapes and ${VAR} interpolation
+//	KEY="a value
+//	spanning lines"         # quoted values can span several lines
+//	KEY=${OTHER}/path       # ${VAR} and $VAR are expanded in unquoted values
+//
+// Variables are expanded from the keys defined earlier in the file first, then
+// from the lookup function given to Parse.
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LookupFunc resolves a variable that is not defined in the file itself.
+type LookupFunc func(key string) (string, bool)
+
+// ParseError reports a malformed line.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+var keyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidKey reports whether key is a valid POSIX environment variable name.
+func ValidKey(key string) bool {
+	return keyRegexp.MatchString(key)
+}
+
+// Parse reads a dotenv file from r. lookup may be nil.
+func Parse(r io.Reader, lookup LookupFunc) (map[string]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		src:    strings.ReplaceAll(string(data), "\r\n", "\n"),
+		line:   1,
+		env:    make(map[string]string),
+		lookup: lookup,
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.env, nil
+}
+
+type parser struct {
+	src    string
+	pos    int
+	line   int
+	env    map[string]string
+	lookup LookupFunc
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parse() error {
+	for p.pos < len(p.src) {
+		line := p.readLine()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			p.line++
+			continue
+		}
+
+		trimmed = strings.TrimPrefix(trimmed, "export ")
+		key, rest, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			return p.errorf("expected KEY=VALUE, got %q", trimmed)
+		}
+		key = strings.TrimSpace(key)
+		if !ValidKey(key) {
+			return p.errorf("invalid variable name %q", key)
+		}
+
+		value, err := p.parseValue(strings.TrimLeft(rest, " \t"))
+		if err != nil {
+			return err
+		}
+		p.env[key] = value
+		p.line++
+	}
+	return nil
+}
+
+// readLine returns the text up to the next newline and advances past it.
+func (p *parser) readLine() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		line := p.src[p.pos:]
+		p.pos = len(p.src)
+		return line
+	}
+	line := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return line
+}
+
+// parseValue parses the value of a variable. rest is what follows the '=' on
+// the current line; quoted values may consume the following lines.
+func (p *parser) parseValue(rest string) (string, error) {
+	if rest == "" {
+		return "", nil
+	}
+
+	quote := rest[0]
+	if quote != '"' && quote != '\'' {
+		// Unquoted values end at the first " #" comment.
+		if idx := strings.Index(rest, " #"); idx >= 0 {
+			rest = rest[:idx]
+		}
+		return p.expand(strings.TrimSpace(rest))
+	}
+
+	startLine := p.line
+	body := rest[1:]
+	for {
+		if end := closingQuote(body, quote); end >= 0 {
+			trailing := strings.TrimSpace(body[end+1:])
+			if trailing != "" && !strings.HasPrefix(trailing, "#") {
+				return "", p.errorf("unexpected characters after closing quote: %q", trailing)
+			}
+			body = body[:end]
+			break
+		}
+		if p.pos >= len(p.src) {
+			p.line = startLine
+			return "", p.errorf("unterminated quoted value")
+		}
+		body += "\n" + p.readLine()
+		p.line++
+	}
+
+	if quote == '\'' {
+		return body, nil
+	}
+	return p.expand(unescape(body))
+}
+
+// closingQuote returns the index of the first unescaped quote in s, or -1.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '"', '\\':
+			sb.WriteByte(s[i])
+		case '$':
+			// Keep the escape so that expand leaves the dollar sign alone.
+			sb.WriteString(`\$`)
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// expand replaces ${VAR} and $VAR references. A dollar sign escaped as \$ is
+// kept literally.
+func (p *parser) expand(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '$':
+			sb.WriteByte('$')
+			i++
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return "", p.errorf("unterminated variable reference in %q", s)
+			}
+			name := s[i+2 : i+end]
+			if !ValidKey(name) {
+				return "", p.errorf("invalid variable reference ${%s}", name)
+			}
+			sb.WriteString(p.resolve(name))
+			i += end
+		case s[i] == '$':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || isAlnum(s[j])) {
+				j++
+			}
+			if j == i+1 {
+				sb.WriteByte('$')
+				continue
+			}
+			sb.WriteString(p.resolve(s[i+1 : j]))
+			i = j - 1
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+func (p *parser) resolve(name string) string {
+	if v, ok := p.env[name]; ok {
+		return v
+	}
+	if p.lookup != nil {
+		if v, ok := p.lookup(name); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
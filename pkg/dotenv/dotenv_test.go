@@ -0,0 +1,97 @@
+package dotenv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/dotenv"
+)
+
+func TestParse(t *testing.T) {
+	input := `
+# database settings
+export DB_HOST=localhost
+DB_PORT = 5432 # default port
+DB_URL=postgres://${DB_HOST}:$DB_PORT/app
+SINGLE='no $DB_HOST expansion'
+DOUBLE="tab\there \"quoted\" ${HOME}"
+MULTI="first line
+second line"
+ESCAPED="costs \$5"
+EMPTY=
+`
+
+	env, err := dotenv.Parse(strings.NewReader(input), func(key string) (string, bool) {
+		if key == "HOME" {
+			return "/home/lou", true
+		}
+		return "", false
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PORT": "5432",
+		"DB_URL":  "postgres://localhost:5432/app",
+		"SINGLE":  "no $DB_HOST expansion",
+		"DOUBLE":  "tab\there \"quoted\" /home/lou",
+		"MULTI":   "first line\nsecond line",
+		"ESCAPED": "costs $5",
+		"EMPTY":   "",
+	}
+
+	if len(env) != len(expected) {
+		t.Errorf("Expected %d variables, got %d: %v", len(expected), len(env), env)
+	}
+	for k, v := range expected {
+		if env[k] != v {
+			t.Errorf("Expected %s to be %q, got %q", k, v, env[k])
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	for key, valid := range map[string]bool{
+		"PATH":      true,
+		"_private":  true,
+		"API_KEY_2": true,
+		"2FA":       false,
+		"log.level": false,
+		"A-B":       false,
+		"":          false,
+	} {
+		if got := dotenv.ValidKey(key); got != valid {
+			t.Errorf("ValidKey(%q): expected %v, got %v", key, valid, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		line  int
+	}{
+		"missing equal sign":  {input: "A=1\nINVALID\n", line: 2},
+		"invalid key":         {input: "1A=1\n", line: 1},
+		"dot in key":          {input: "A=1\nlog.level=debug\n", line: 2},
+		"unterminated quote":  {input: "A=1\nB=\"open\nstill open\n", line: 2},
+		"garbage after quote": {input: "A='x' y\n", line: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := dotenv.Parse(strings.NewReader(tt.input), nil)
+
+			var parseErr *dotenv.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a ParseError, got %v", err)
+			}
+			if parseErr.Line != tt.line {
+				t.Errorf("Expected error on line %d, got %d (%v)", tt.line, parseErr.Line, err)
+			}
+		})
+	}
+}
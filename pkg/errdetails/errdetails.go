@@ -0,0 +1,152 @@
+// Package errdetails decodes the google.rpc error details the API attaches to
+// its errors. The messages are decoded from the wire format directly, as
+// their generated code is not a dependency of the CLI.
+package errdetails
+
+import (
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	badRequestType          = "google.rpc.BadRequest"
+	quotaFailureType        = "google.rpc.QuotaFailure"
+	preconditionFailureType = "google.rpc.PreconditionFailure"
+	retryInfoType           = "google.rpc.RetryInfo"
+	requestInfoType         = "google.rpc.RequestInfo"
+	errorInfoType           = "google.rpc.ErrorInfo"
+	localizedMessageType    = "google.rpc.LocalizedMessage"
+)
+
+// requestIDHeaders carry the request ID when the error has no RequestInfo.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id"}
+
+// Violation is a reason why a request failed, e.g. an invalid field.
+type Violation struct {
+	// Kind is field, quota or precondition.
+	Kind string `json:"kind"`
+	// Subject is what the violation is about: a field path, a quota or a
+	// resource.
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description"`
+}
+
+// Details is what the CLI understands of the details of an API error.
+type Details struct {
+	Violations []Violation
+	// Reason and Domain come from an ErrorInfo detail.
+	Reason string
+	Domain string
+	// Message is a localized message meant for the user, if any.
+	Message    string
+	RetryDelay time.Duration
+	RequestID  string
+}
+
+// FromError extracts the details of an API error. It returns nil when err is
+// not an API error.
+func FromError(err error) *Details {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return nil
+	}
+
+	d := &Details{}
+	for _, detail := range connectErr.Details() {
+		b := detail.Bytes()
+		switch detail.Type() {
+		case badRequestType:
+			for _, v := range repeated(b, 1) {
+				d.Violations = append(d.Violations, Violation{Kind: "field", Subject: str(v, 1), Description: str(v, 2)})
+			}
+		case quotaFailureType:
+			for _, v := range repeated(b, 1) {
+				d.Violations = append(d.Violations, Violation{Kind: "quota", Subject: str(v, 1), Description: str(v, 2)})
+			}
+		case preconditionFailureType:
+			for _, v := range repeated(b, 1) {
+				d.Violations = append(d.Violations, Violation{Kind: "precondition", Subject: str(v, 2), Description: str(v, 3)})
+			}
+		case retryInfoType:
+			if delay, ok := RetryDelay(b); ok {
+				d.RetryDelay = delay
+			}
+		case requestInfoType:
+			d.RequestID = str(b, 1)
+		case errorInfoType:
+			d.Reason = str(b, 1)
+			d.Domain = str(b, 2)
+		case localizedMessageType:
+			d.Message = str(b, 2)
+		}
+	}
+
+	if d.RequestID == "" {
+		for _, h := range requestIDHeaders {
+			if id := connectErr.Meta().Get(h); id != "" {
+				d.RequestID = id
+				break
+			}
+		}
+	}
+
+	return d
+}
+
+// RetryDelay decodes the retry_delay field of a google.rpc.RetryInfo message.
+func RetryDelay(b []byte) (time.Duration, bool) {
+	values := repeated(b, 1)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	var d durationpb.Duration
+	if err := proto.Unmarshal(values[len(values)-1], &d); err != nil {
+		return 0, false
+	}
+	return d.AsDuration(), true
+}
+
+// repeated returns the values of a length-delimited field, in order. Decoding
+// stops at the first malformed field.
+func repeated(b []byte, field protowire.Number) [][]byte {
+	var values [][]byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return values
+		}
+		b = b[n:]
+
+		if num == field && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return values
+			}
+			values = append(values, v)
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return values
+		}
+		b = b[n:]
+	}
+	return values
+}
+
+// str returns the last value of a string field, as proto3 does.
+func str(b []byte, field protowire.Number) string {
+	values := repeated(b, field)
+	if len(values) == 0 {
+		return ""
+	}
+	return string(values[len(values)-1])
+}
@@ -0,0 +1,112 @@
+package errdetails_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/baepo-cloud/baepo-cli/pkg/errdetails"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"github.com/baepo-cloud/baepo-proto/go/baepo/api/v1/apiv1pbconnect"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// message encodes string fields in order, as field numbers 1, 2, ...
+func message(values ...string) []byte {
+	var b []byte
+	for i, v := range values {
+		b = protowire.AppendTag(b, protowire.Number(i+1), protowire.BytesType)
+		b = protowire.AppendString(b, v)
+	}
+	return b
+}
+
+// embed encodes each message as a repeated field 1.
+func embed(messages ...[]byte) []byte {
+	var b []byte
+	for _, m := range messages {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, m)
+	}
+	return b
+}
+
+func detail(typ string, value []byte) string {
+	return fmt.Sprintf(`{"type":%q,"value":%q}`, typ, base64.RawStdEncoding.EncodeToString(value))
+}
+
+func TestFromError(t *testing.T) {
+	delay, err := proto.Marshal(durationpb.New(1500 * time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := []string{
+		detail("google.rpc.BadRequest", embed(
+			message("spec.containers[0].image", "must not be empty"),
+			message("spec.cpus", "must be a power of two"),
+		)),
+		detail("google.rpc.QuotaFailure", embed(message("workspace:w1", "CPU quota exceeded"))),
+		detail("google.rpc.RetryInfo", embed(delay)),
+		detail("google.rpc.RequestInfo", message("req-42")),
+		detail("google.rpc.ErrorInfo", message("INVALID_SPEC", "baepo.cloud")),
+		detail("google.example.Unknown", message("ignored")),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"code":"invalid_argument","message":"invalid spec","details":[%s,%s,%s,%s,%s,%s]}`,
+			details[0], details[1], details[2], details[3], details[4], details[5])
+	}))
+	defer srv.Close()
+
+	client := apiv1pbconnect.NewMachineServiceClient(srv.Client(), srv.URL)
+	_, err = client.Create(context.Background(), connect.NewRequest(&apiv1pb.MachineCreateRequest{}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("Expected an invalid argument error, got %v", err)
+	}
+
+	d := errdetails.FromError(err)
+	expected := []errdetails.Violation{
+		{Kind: "field", Subject: "spec.containers[0].image", Description: "must not be empty"},
+		{Kind: "field", Subject: "spec.cpus", Description: "must be a power of two"},
+		{Kind: "quota", Subject: "workspace:w1", Description: "CPU quota exceeded"},
+	}
+	if len(d.Violations) != len(expected) {
+		t.Fatalf("Expected violations %v, got %v", expected, d.Violations)
+	}
+	for i := range expected {
+		if d.Violations[i] != expected[i] {
+			t.Errorf("Expected violation %v, got %v", expected[i], d.Violations[i])
+		}
+	}
+	if d.RetryDelay != 1500*time.Millisecond {
+		t.Errorf("Expected a retry delay of 1.5s, got %s", d.RetryDelay)
+	}
+	if d.RequestID != "req-42" {
+		t.Errorf("Expected request ID req-42, got %q", d.RequestID)
+	}
+	if d.Reason != "INVALID_SPEC" || d.Domain != "baepo.cloud" {
+		t.Errorf("Unexpected error info %q %q", d.Domain, d.Reason)
+	}
+}
+
+func TestFromErrorRequestIDHeader(t *testing.T) {
+	err := connect.NewError(connect.CodeInternal, nil)
+	err.Meta().Set("X-Request-Id", "req-7")
+
+	if d := errdetails.FromError(err); d.RequestID != "req-7" {
+		t.Errorf("Expected request ID req-7, got %q", d.RequestID)
+	}
+	if d := errdetails.FromError(fmt.Errorf("not an API error")); d != nil {
+		t.Errorf("Expected no details, got %+v", d)
+	}
+}
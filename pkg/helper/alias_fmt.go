@@ -0,0 +1,27 @@
+package helper
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+type AliasFmt struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+}
+
+func AliasFmtMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Name",
+			FormatFunc: func(obj *AliasFmt) string {
+				return obj.Name
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Expansion",
+			FormatFunc: func(obj *AliasFmt) string {
+				return obj.Expansion
+			},
+		},
+	}
+}
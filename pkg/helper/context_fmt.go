@@ -1,6 +1,10 @@
 package helper
 
 import (
+	"encoding/json"
+	"maps"
+	"slices"
+
 	"github.com/baepo-cloud/baepo-cli/pkg/config"
 	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
 )
@@ -11,6 +15,54 @@ type ContextFmt struct {
 	Value   config.Context
 }
 
+// contextJSON is the JSON form of a context. Credentials other than the secret
+// key are left out, and only the names of the custom headers are given as
+// their values are often secrets too.
+type contextJSON struct {
+	Name               string   `json:"name"`
+	Current            bool     `json:"current"`
+	URL                string   `json:"url"`
+	WorkspaceID        string   `json:"workspace_id,omitempty"`
+	UserID             string   `json:"user_id,omitempty"`
+	SecretKey          string   `json:"secret_key,omitempty"`
+	Timeout            string   `json:"timeout,omitempty"`
+	CAFile             string   `json:"ca_file,omitempty"`
+	ClientCert         string   `json:"client_cert,omitempty"`
+	ClientKey          string   `json:"client_key,omitempty"`
+	InsecureSkipVerify bool     `json:"insecure_skip_verify,omitempty"`
+	Proxy              string   `json:"proxy,omitempty"`
+	Headers            []string `json:"headers,omitempty"`
+	Protocol           string   `json:"protocol,omitempty"`
+	Encoding           string   `json:"encoding,omitempty"`
+	Compression        string   `json:"compression,omitempty"`
+	OAuthIssuer        string   `json:"oauth_issuer,omitempty"`
+	OAuthClientID      string   `json:"oauth_client_id,omitempty"`
+}
+
+func (c *ContextFmt) MarshalJSON() ([]byte, error) {
+	v := c.Value
+	return json.Marshal(contextJSON{
+		Name:               c.Name,
+		Current:            c.Current,
+		URL:                v.URL,
+		WorkspaceID:        v.WorkspaceID,
+		UserID:             v.UserID,
+		SecretKey:          v.SecretKey,
+		Timeout:            v.Timeout,
+		CAFile:             v.CAFile,
+		ClientCert:         v.ClientCert,
+		ClientKey:          v.ClientKey,
+		InsecureSkipVerify: v.InsecureSkipVerify,
+		Proxy:              v.Proxy,
+		Headers:            slices.Sorted(maps.Keys(v.Headers)),
+		Protocol:           v.Protocol,
+		Encoding:           v.Encoding,
+		Compression:        v.Compression,
+		OAuthIssuer:        v.OAuthIssuer,
+		OAuthClientID:      v.OAuthClientID,
+	})
+}
+
 func ContextFmtMapping() []any {
 	return []any{
 		iostream.FieldConfig{
@@ -0,0 +1,38 @@
+package helper_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+)
+
+func TestContextFmtJSONRedacted(t *testing.T) {
+	c := &helper.ContextFmt{
+		Name: "ci",
+		Value: config.Context{
+			URL:         "https://api.baepo.cloud/",
+			Token:       "tok-secret",
+			AccessToken: "at-secret",
+			Headers:     map[string]string{"X-Gateway-Key": "gw-secret"},
+		},
+	}
+
+	out, err := json.Marshal([]*helper.ContextFmt{c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"tok-secret", "at-secret", "gw-secret"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("Expected %q to be left out of %s", secret, out)
+		}
+	}
+	for _, expected := range []string{`"name":"ci"`, `"url":"https://api.baepo.cloud/"`, `"headers":["X-Gateway-Key"]`} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("Expected %s in %s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package helper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+func MachineEventMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Time",
+			Width:       len(time.DateTime),
+			FormatFunc: func(obj *corev1pb.MachineEvent) string {
+				return TimestampToHumanString(obj.GetTimestamp())
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Event",
+			// Followed events are printed as they come, the longest type
+			// keeps their rows aligned.
+			Width: len("Reconciliation Completed"),
+			FormatFunc: func(obj *corev1pb.MachineEvent) string {
+				return MachineEventTypeToHumanString(obj)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Details",
+			FormatFunc: func(obj *corev1pb.MachineEvent) string {
+				return MachineEventDetailsToHumanString(obj)
+			},
+		},
+	}
+}
+
+func MachineEventTypeToHumanString(e *corev1pb.MachineEvent) string {
+	switch e.GetEvent().(type) {
+	case *corev1pb.MachineEvent_StateChangedEvent:
+		return "State Changed"
+	case *corev1pb.MachineEvent_Started_:
+		return "Started"
+	case *corev1pb.MachineEvent_TerminatedEvent:
+		return "Terminated"
+	case *corev1pb.MachineEvent_DesiredStateChangedEvent:
+		return "Desired State Changed"
+	case *corev1pb.MachineEvent_ReconciliationStartedEvent:
+		return "Reconciliation Started"
+	case *corev1pb.MachineEvent_ReconciliationCompletedEvent:
+		return "Reconciliation Completed"
+	case *corev1pb.MachineEvent_HealthcheckEvent:
+		if e.GetHealthcheckEvent().Error != nil {
+			return "Healthcheck Failed"
+		}
+		return "Healthcheck Passed"
+	default:
+		return blank
+	}
+}
+
+func MachineEventDetailsToHumanString(e *corev1pb.MachineEvent) string {
+	switch ev := e.GetEvent().(type) {
+	case *corev1pb.MachineEvent_StateChangedEvent:
+		return MachineStateToHumanString(ev.StateChangedEvent.GetState())
+	case *corev1pb.MachineEvent_Started_:
+		if ev.Started.GetExpiresAt() != nil {
+			return fmt.Sprintf("Expires at %s", TimestampToHumanString(ev.Started.GetExpiresAt()))
+		}
+		return blank
+	case *corev1pb.MachineEvent_TerminatedEvent:
+		details := MachineTerminationCauseToHumanString(ev.TerminatedEvent.GetCause())
+		if ev.TerminatedEvent.GetTerminationDetails() != "" {
+			details += ": " + ev.TerminatedEvent.GetTerminationDetails()
+		}
+		return details
+	case *corev1pb.MachineEvent_DesiredStateChangedEvent:
+		return MachineDesiredStateToHumanString(ev.DesiredStateChangedEvent.GetDesiredState())
+	case *corev1pb.MachineEvent_ReconciliationStartedEvent:
+		return MachineDesiredStateToHumanString(ev.ReconciliationStartedEvent.GetDesiredState())
+	case *corev1pb.MachineEvent_ReconciliationCompletedEvent:
+		if ev.ReconciliationCompletedEvent.GetError() != "" {
+			return ev.ReconciliationCompletedEvent.GetError()
+		}
+		return MachineDesiredStateToHumanString(ev.ReconciliationCompletedEvent.GetDesiredState())
+	case *corev1pb.MachineEvent_HealthcheckEvent:
+		if ev.HealthcheckEvent.GetError() != "" {
+			return ev.HealthcheckEvent.GetError()
+		}
+		return blank
+	default:
+		return blank
+	}
+}
@@ -3,11 +3,11 @@ package helper
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
 	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
 	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
-	"github.com/dustin/go-humanize"
 )
 
 // MachineArrayConfig returns the declarative configuration for mapping Machine arrays.
@@ -44,61 +44,7 @@ func MachineMapping() []any {
 			Path:        "Spec",
 			DisplayName: "Spec",
 			Full:        true,
-			Fields: []any{
-				iostream.FieldConfig{
-					DisplayName: "CPUs",
-					FormatFunc: func(obj *corev1pb.MachineSpec) string {
-						return fmt.Sprint(obj.Cpus)
-					},
-				},
-				iostream.FieldConfig{
-					DisplayName: "Memory",
-					FormatFunc: func(obj *corev1pb.MachineSpec) string {
-						return humanize.Bytes(obj.MemoryMb * 1024 * 1024)
-					},
-				},
-				iostream.ArrayConfig{
-					Path:        "Containers",
-					DisplayName: "Containers",
-					ObjectConfig: &iostream.ObjectConfig{
-						Fields: []any{
-							iostream.FieldConfig{
-								DisplayName: "Image",
-								FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
-									return fmt.Sprint(obj.Image)
-								},
-							},
-							iostream.FieldConfig{
-								DisplayName: "Env",
-								FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
-									if len(obj.Env) == 0 {
-										return "-"
-									}
-									return EnvToHumanString(obj.Env)
-								},
-							},
-							iostream.FieldConfig{
-								DisplayName: "Healthcheck",
-								FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
-									if obj.Healthcheck == nil {
-										return "-"
-									}
-									return MachineContainerHealthcheckSpecToHumanString(obj.Healthcheck)
-								},
-							},
-							iostream.FieldConfig{
-								DisplayName: "Command",
-								FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
-									if len(obj.Command) == 0 {
-										return "-"
-									}
-									return strings.Join(obj.Command, " ")
-								},
-							},
-						},
-					},
-				},
-			},
+			Fields:      machineSpecFields(),
 		},
 		iostream.FieldConfig{
 			DisplayName: "State",
@@ -124,6 +70,15 @@ func MachineMapping() []any {
 				return TimestampToHumanString(obj.GetExpiresAt())
 			},
 		},
+		iostream.FieldConfig{
+			DisplayName: "Expires In",
+			FormatFunc: func(obj *apiv1pb.Machine) string {
+				if obj.TerminatedAt != nil {
+					return blank
+				}
+				return ExpiresInToHumanString(obj.GetExpiresAt())
+			},
+		},
 		iostream.FieldConfig{
 			DisplayName: "Terminated At",
 			FormatFunc: func(obj *apiv1pb.Machine) string {
@@ -150,3 +105,72 @@ func MachineMapping() []any {
 		},
 	}
 }
+
+// machineSpecFields is the mapping of a MachineSpec, shared by every mapping
+// that embeds one.
+func machineSpecFields() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "CPUs",
+			FormatFunc: func(obj *corev1pb.MachineSpec) string {
+				return fmt.Sprint(obj.Cpus)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Memory",
+			FormatFunc: func(obj *corev1pb.MachineSpec) string {
+				return MemoryMBToHumanString(obj.MemoryMb)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "TTL",
+			FormatFunc: func(obj *corev1pb.MachineSpec) string {
+				if obj.Timeout == nil {
+					return blank
+				}
+				return DurationToHumanString(time.Duration(obj.GetTimeout()) * time.Second)
+			},
+		},
+		iostream.ArrayConfig{
+			Path:        "Containers",
+			DisplayName: "Containers",
+			ObjectConfig: &iostream.ObjectConfig{
+				Fields: []any{
+					iostream.FieldConfig{
+						DisplayName: "Image",
+						FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
+							return fmt.Sprint(obj.Image)
+						},
+					},
+					iostream.FieldConfig{
+						DisplayName: "Env",
+						FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
+							if len(obj.Env) == 0 {
+								return "-"
+							}
+							return EnvToHumanString(obj.Env)
+						},
+					},
+					iostream.FieldConfig{
+						DisplayName: "Healthcheck",
+						FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
+							if obj.Healthcheck == nil {
+								return "-"
+							}
+							return MachineContainerHealthcheckSpecToHumanString(obj.Healthcheck)
+						},
+					},
+					iostream.FieldConfig{
+						DisplayName: "Command",
+						FormatFunc: func(obj *corev1pb.MachineContainerSpec) string {
+							if len(obj.Command) == 0 {
+								return "-"
+							}
+							return strings.Join(obj.Command, " ")
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+)
+
+// MachineCreateRequestMapping is used to show the request of a dry run.
+func MachineCreateRequestMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Workspace ID",
+			FormatFunc: func(obj *apiv1pb.MachineCreateRequest) string {
+				return obj.GetWorkspaceId()
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Name",
+			FormatFunc: func(obj *apiv1pb.MachineCreateRequest) string {
+				return obj.GetName()
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Metadata",
+			FormatFunc: func(obj *apiv1pb.MachineCreateRequest) string {
+				return EnvToHumanString(obj.GetMetadata())
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Start",
+			FormatFunc: func(obj *apiv1pb.MachineCreateRequest) string {
+				if obj.GetStart() {
+					return "Yes"
+				}
+				return "No"
+			},
+		},
+		iostream.ObjectConfig{
+			Path:        "Spec",
+			DisplayName: "Spec",
+			Full:        true,
+			Fields:      machineSpecFields(),
+		},
+	}
+}
+
+func MachineStartRequestMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Machine ID",
+			FormatFunc: func(obj *apiv1pb.MachineStartRequest) string {
+				return obj.GetMachineId()
+			},
+		},
+	}
+}
+
+func MachineTerminateRequestMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Machine ID",
+			FormatFunc: func(obj *apiv1pb.MachineTerminateRequest) string {
+				return obj.GetMachineId()
+			},
+		},
+	}
+}
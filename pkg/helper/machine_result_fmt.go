@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+const (
+	MachineResultOK     = "ok"
+	MachineResultFailed = "failed"
+)
+
+// MachineResultFmt is the outcome of a bulk action on a single machine.
+type MachineResultFmt struct {
+	MachineID string `json:"machine_id"`
+	Outcome   string `json:"outcome"`
+	Error     string `json:"error,omitempty"`
+}
+
+func MachineResultFmtMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "ID",
+			FormatFunc: func(obj *MachineResultFmt) string {
+				return obj.MachineID
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Outcome",
+			FormatFunc: func(obj *MachineResultFmt) string {
+				return obj.Outcome
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Error",
+			FormatFunc: func(obj *MachineResultFmt) string {
+				if obj.Error == "" {
+					return blank
+				}
+				return obj.Error
+			},
+		},
+	}
+}
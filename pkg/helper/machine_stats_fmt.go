@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"fmt"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+// MachineStatsFmt is the number of machines in a state and the resources
+// they were given.
+type MachineStatsFmt struct {
+	State    string `json:"state"`
+	Machines int    `json:"machines"`
+	Cpus     uint64 `json:"cpus"`
+	MemoryMB uint64 `json:"memory_mb"`
+}
+
+func MachineStatsFmtMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "State",
+			// The longest state, so that refreshed tables keep their layout.
+			Width: len("Terminating"),
+			FormatFunc: func(obj *MachineStatsFmt) string {
+				return obj.State
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Machines",
+			FormatFunc: func(obj *MachineStatsFmt) string {
+				return fmt.Sprint(obj.Machines)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "CPUs",
+			FormatFunc: func(obj *MachineStatsFmt) string {
+				return fmt.Sprint(obj.Cpus)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Memory",
+			FormatFunc: func(obj *MachineStatsFmt) string {
+				return MemoryMBToHumanString(obj.MemoryMB)
+			},
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"encoding/json"
+
+	apiv1pb "github.com/baepo-cloud/baepo-proto/go/baepo/api/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// MachineUpdateFmt is the JSON output of machine update: the spec changes,
+// along with the request that would be sent with --dry-run, or the machine
+// that replaced the updated one.
+type MachineUpdateFmt struct {
+	Changes []*SpecChangeFmt
+	Request *apiv1pb.MachineCreateRequest
+	Machine *apiv1pb.Machine
+}
+
+type machineUpdateJSON struct {
+	Changes []*SpecChangeFmt `json:"changes"`
+	Request json.RawMessage  `json:"request,omitempty"`
+	Machine json.RawMessage  `json:"machine,omitempty"`
+}
+
+func (u *MachineUpdateFmt) MarshalJSON() ([]byte, error) {
+	out := machineUpdateJSON{Changes: u.Changes}
+	if u.Request != nil {
+		b, err := protojson.Marshal(u.Request)
+		if err != nil {
+			return nil, err
+		}
+		out.Request = b
+	}
+	if u.Machine != nil {
+		b, err := protojson.Marshal(u.Machine)
+		if err != nil {
+			return nil, err
+		}
+		out.Machine = b
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,38 @@
+package helper
+
+import (
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+)
+
+func PluginMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Name",
+			FormatFunc: func(obj *plugin.Plugin) string {
+				return obj.Name
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Path",
+			FormatFunc: func(obj *plugin.Plugin) string {
+				return obj.Path
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Source",
+			FormatFunc: func(obj *plugin.Plugin) string {
+				return obj.Source
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Shadowed By",
+			FormatFunc: func(obj *plugin.Plugin) string {
+				if obj.ShadowedBy == "" {
+					return blank
+				}
+				return obj.ShadowedBy
+			},
+		},
+	}
+}
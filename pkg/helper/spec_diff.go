@@ -0,0 +1,120 @@
+package helper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+// maskedValue replaces the values of environment variables in spec diffs.
+const maskedValue = "********"
+
+// SpecChangeFmt is a single field that differs between two machine specs.
+type SpecChangeFmt struct {
+	Field   string `json:"field"`
+	Current string `json:"current,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+func SpecChangeFmtMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "Field",
+			FormatFunc: func(obj *SpecChangeFmt) string {
+				return obj.Field
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Current",
+			FormatFunc: func(obj *SpecChangeFmt) string {
+				if obj.Current == "" {
+					return blank
+				}
+				return obj.Current
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "New",
+			FormatFunc: func(obj *SpecChangeFmt) string {
+				if obj.New == "" {
+					return blank
+				}
+				return obj.New
+			},
+		},
+	}
+}
+
+// MachineSpecDiff returns the fields that differ between current and next,
+// sorted by field path.
+func MachineSpecDiff(current, next *corev1pb.MachineSpec) []*SpecChangeFmt {
+	a := flattenMachineSpec(current)
+	b := flattenMachineSpec(next)
+
+	fields := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		fields = append(fields, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	slices.Sort(fields)
+
+	changes := make([]*SpecChangeFmt, 0)
+	for _, f := range fields {
+		if a[f] == b[f] {
+			continue
+		}
+		change := &SpecChangeFmt{Field: f, Current: a[f], New: b[f]}
+		// Environment variables often hold secrets, only their keys are
+		// shown.
+		if strings.Contains(f, ".env.") {
+			change.Current = maskValue(change.Current)
+			change.New = maskValue(change.New)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func maskValue(v string) string {
+	if v == "" {
+		return ""
+	}
+	return maskedValue
+}
+
+func flattenMachineSpec(spec *corev1pb.MachineSpec) map[string]string {
+	out := make(map[string]string)
+	if spec == nil {
+		return out
+	}
+
+	out["cpus"] = fmt.Sprint(spec.Cpus)
+	out["memory"] = MemoryMBToHumanString(spec.MemoryMb)
+	if spec.Timeout != nil {
+		out["ttl"] = DurationToHumanString(time.Duration(spec.GetTimeout()) * time.Second)
+	}
+
+	for i, c := range spec.Containers {
+		prefix := fmt.Sprintf("containers[%d].", i)
+		out[prefix+"image"] = c.Image
+		for k, v := range c.Env {
+			out[prefix+"env."+k] = v
+		}
+		if len(c.Command) > 0 {
+			out[prefix+"command"] = strings.Join(c.Command, " ")
+		}
+		if hc := c.Healthcheck; hc != nil {
+			out[prefix+"healthcheck"] = MachineContainerHealthcheckSpecToHumanString(hc)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"testing"
+
+	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
+)
+
+func TestMachineSpecDiffMasksEnv(t *testing.T) {
+	current := &corev1pb.MachineSpec{Cpus: 1, Containers: []*corev1pb.MachineContainerSpec{
+		{Image: "app:v1", Env: map[string]string{"DB_PASSWORD": "old-secret", "DEBUG": "1"}},
+	}}
+	next := &corev1pb.MachineSpec{Cpus: 1, Containers: []*corev1pb.MachineContainerSpec{
+		{Image: "app:v2", Env: map[string]string{"DB_PASSWORD": "new-secret", "TOKEN": "t0k3n"}},
+	}}
+
+	expected := []SpecChangeFmt{
+		{Field: "containers[0].env.DB_PASSWORD", Current: maskedValue, New: maskedValue},
+		{Field: "containers[0].env.DEBUG", Current: maskedValue},
+		{Field: "containers[0].env.TOKEN", New: maskedValue},
+		{Field: "containers[0].image", Current: "app:v1", New: "app:v2"},
+	}
+
+	changes := MachineSpecDiff(current, next)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d", len(expected), len(changes))
+	}
+	for i, c := range changes {
+		if *c != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], *c)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package helper
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	corev1pb "github.com/baepo-cloud/baepo-proto/go/baepo/core/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -33,6 +35,25 @@ func MachineStateToHumanString(s corev1pb.MachineState) string {
 	}
 }
 
+// ParseMachineState converts a human state name such as "running" or
+// "Terminated" back to a MachineState. The comparison is case-insensitive.
+func ParseMachineState(s string) (corev1pb.MachineState, error) {
+	for _, state := range []corev1pb.MachineState{
+		corev1pb.MachineState_MachineState_Pending,
+		corev1pb.MachineState_MachineState_Starting,
+		corev1pb.MachineState_MachineState_Running,
+		corev1pb.MachineState_MachineState_Degraded,
+		corev1pb.MachineState_MachineState_Error,
+		corev1pb.MachineState_MachineState_Terminating,
+		corev1pb.MachineState_MachineState_Terminated,
+	} {
+		if strings.EqualFold(s, MachineStateToHumanString(state)) {
+			return state, nil
+		}
+	}
+	return corev1pb.MachineState_MachineState_Unknown, fmt.Errorf("unknown machine state %q", s)
+}
+
 func MachineDesiredStateToHumanString(s corev1pb.MachineDesiredState) string {
 	switch s {
 	case corev1pb.MachineDesiredState_MachineDesiredState_Pending:
@@ -50,7 +71,35 @@ func TimestampToHumanString(at *timestamppb.Timestamp) string {
 	if at == nil {
 		return blank
 	}
-	return at.AsTime().Format("2006-01-02 15:04:05")
+	return at.AsTime().Format(time.DateTime)
+}
+
+// ExpiresInToHumanString renders an expiry timestamp relative to now, e.g.
+// "1h30m", or "Expired" once it is in the past.
+func ExpiresInToHumanString(at *timestamppb.Timestamp) string {
+	if at == nil {
+		return blank
+	}
+	d := time.Until(at.AsTime())
+	if d <= 0 {
+		return "Expired"
+	}
+	return DurationToHumanString(d)
+}
+
+// DurationToHumanString renders a duration rounded to the minute, e.g. "2d3h",
+// "1h30m" or "5m".
+func DurationToHumanString(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
 }
 
 func MachineTerminationCauseToHumanString(cause corev1pb.MachineTerminationCause) string {
@@ -114,6 +163,7 @@ func MachineContainerHealthcheckSpecToHumanString(hc *corev1pb.MachineContainerH
 			for k, v := range http.Headers {
 				headerParts = append(headerParts, fmt.Sprintf("%s=%s", k, v))
 			}
+			slices.Sort(headerParts)
 			parts = append(parts, fmt.Sprintf("Headers: %s", strings.Join(headerParts, ", ")))
 		}
 	}
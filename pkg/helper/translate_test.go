@@ -0,0 +1,47 @@
+package helper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDurationToHumanString(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                               "<1m",
+		59 * time.Second:                "<1m",
+		time.Minute:                     "1m",
+		59*time.Minute + 59*time.Second: "59m",
+		time.Hour:                       "1h0m",
+		90 * time.Minute:                "1h30m",
+		23*time.Hour + 59*time.Minute:   "23h59m",
+		24 * time.Hour:                  "1d0h",
+		51 * time.Hour:                  "2d3h",
+	}
+
+	for d, expected := range tests {
+		if got := helper.DurationToHumanString(d); got != expected {
+			t.Errorf("DurationToHumanString(%s) = %q, expected %q", d, got, expected)
+		}
+	}
+}
+
+func TestExpiresInToHumanString(t *testing.T) {
+	tests := map[string]struct {
+		at       *timestamppb.Timestamp
+		expected string
+	}{
+		"no expiry": {at: nil, expected: "-"},
+		"expired":   {at: timestamppb.New(time.Now().Add(-time.Minute)), expected: "Expired"},
+		"in 90m":    {at: timestamppb.New(time.Now().Add(90*time.Minute + 30*time.Second)), expected: "1h30m"},
+		"in 2 days": {at: timestamppb.New(time.Now().Add(48*time.Hour + 30*time.Second)), expected: "2d0h"},
+	}
+
+	for name, tt := range tests {
+		if got := helper.ExpiresInToHumanString(tt.at); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", name, tt.expected, got)
+		}
+	}
+}
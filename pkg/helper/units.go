@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dustin/go-humanize"
+)
+
+var memoryUnits = map[string]float64{
+	"":    1,
+	"m":   1,
+	"mb":  1,
+	"mi":  1,
+	"mib": 1,
+	"g":   1024,
+	"gb":  1024,
+	"gi":  1024,
+	"gib": 1024,
+	"t":   1024 * 1024,
+	"tb":  1024 * 1024,
+	"ti":  1024 * 1024,
+	"tib": 1024 * 1024,
+}
+
+// ParseMemoryMB parses a memory size such as "512M", "2GiB" or "1.5g" and
+// returns it in MB. Units are case-insensitive powers of 1024, as is usual for
+// memory, and a plain number is a number of MB.
+func ParseMemoryMB(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+	if idx < 0 {
+		idx = len(s)
+	}
+
+	number, unit := s[:idx], strings.ToLower(strings.TrimSpace(s[idx:]))
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory size %q", s)
+	}
+
+	factor, ok := memoryUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid memory unit %q in %q, expected M, G or T", s[idx:], s)
+	}
+
+	mb := value * factor
+	if mb != float64(uint64(mb)) {
+		return 0, fmt.Errorf("memory size %q is not a whole number of MB", s)
+	}
+	return uint64(mb), nil
+}
+
+// MemoryMBToHumanString formats a memory size given in MB, e.g. "2.0 GiB".
+func MemoryMBToHumanString(mb uint64) string {
+	return humanize.IBytes(mb * 1024 * 1024)
+}
@@ -0,0 +1,36 @@
+package helper_test
+
+import (
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/helper"
+)
+
+func TestParseMemoryMB(t *testing.T) {
+	tests := map[string]uint64{
+		"1024":   1024,
+		"512M":   512,
+		"512MiB": 512,
+		"2GiB":   2048,
+		"2 GB":   2048,
+		"1.5g":   1536,
+		"1Ti":    1024 * 1024,
+	}
+
+	for input, expected := range tests {
+		mb, err := helper.ParseMemoryMB(input)
+		if err != nil {
+			t.Errorf("ParseMemoryMB(%q) returned an error: %v", input, err)
+			continue
+		}
+		if mb != expected {
+			t.Errorf("ParseMemoryMB(%q) = %d, expected %d", input, mb, expected)
+		}
+	}
+
+	for _, input := range []string{"", "GiB", "2XB", "-1G", "0.3M"} {
+		if _, err := helper.ParseMemoryMB(input); err == nil {
+			t.Errorf("ParseMemoryMB(%q) expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/iostream"
+)
+
+// WorkspaceFmt is a workspace known to the CLI.
+type WorkspaceFmt struct {
+	ID string `json:"id"`
+	// Current is set for the workspace commands act on.
+	Current bool `json:"current"`
+	// User is set for the workspace of the logged in user.
+	User bool `json:"user"`
+	// Contexts are the names of the contexts using the workspace.
+	Contexts []string `json:"contexts,omitempty"`
+}
+
+func WorkspaceFmtMapping() []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: "ID",
+			FormatFunc: func(obj *WorkspaceFmt) string {
+				return obj.ID
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Current",
+			FormatFunc: func(obj *WorkspaceFmt) string {
+				if obj.Current {
+					return "Yes"
+				}
+				return "No"
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "User Workspace",
+			FormatFunc: func(obj *WorkspaceFmt) string {
+				if obj.User {
+					return "Yes"
+				}
+				return "No"
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: "Contexts",
+			FormatFunc: func(obj *WorkspaceFmt) string {
+				if len(obj.Contexts) == 0 {
+					return blank
+				}
+				return strings.Join(obj.Contexts, ", ")
+			},
+		},
+	}
+}
@@ -3,6 +3,8 @@
 package iostream
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -18,6 +20,8 @@ type IOStream struct {
 	Stdout io.Writer
 	// Stderr is the writer for error output
 	Stderr io.Writer
+	// Stdin is the reader for standard input
+	Stdin io.Reader
 }
 
 // ErrorMessage represents an error message with optional details
@@ -32,6 +36,7 @@ func New(jsonOutput bool) *IOStream {
 		JSONOutput: jsonOutput,
 		Stdout:     os.Stdout,
 		Stderr:     os.Stderr,
+		Stdin:      os.Stdin,
 	}
 }
 
@@ -55,11 +60,81 @@ func (s *IOStream) Error(str string, args ...interface{}) {
 	}
 }
 
+// Warning outputs a warning message to stderr
+func (s *IOStream) Warning(str string, args ...interface{}) {
+	msg := fmt.Sprintf(str, args...)
+	if s.JSONOutput {
+		s.writeJSON(s.Stderr, map[string]string{"warning": msg})
+	} else {
+		fmt.Fprintln(s.Stderr, "Warning: "+msg)
+	}
+}
+
+// CanPrompt reports whether stdin is a terminal a question can be answered
+// from.
+func (s *IOStream) CanPrompt() bool {
+	return isTerminal(s.Stdin)
+}
+
+// CanRefresh reports whether stdout is a terminal whose screen can be redrawn.
+func (s *IOStream) CanRefresh() bool {
+	return !s.JSONOutput && isTerminal(s.Stdout)
+}
+
+// ClearScreen moves the cursor to the top left corner of the terminal and
+// clears it, see CanRefresh.
+func (s *IOStream) ClearScreen() {
+	fmt.Fprint(s.Stdout, "\033[H\033[2J")
+}
+
+func isTerminal(v any) bool {
+	f, ok := v.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// The null device is a character device too.
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(stat, null)
+}
+
+// Confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Any answer other than "y" or "yes" is treated as a refusal.
+func (s *IOStream) Confirm(str string, args ...interface{}) (bool, error) {
+	fmt.Fprintf(s.Stderr, "%s [y/N]: ", fmt.Sprintf(str, args...))
+
+	answer, err := bufio.NewReader(s.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
 // ErrorOptions represents options for customizing error output
 type ErrorOptions struct {
 	Error   string `json:"error"`
 	Details string `json:"details,omitempty"`
 	Code    string `json:"code,omitempty"`
+	// Violations are listed under the error, one per line.
+	Violations []ErrorViolation `json:"violations,omitempty"`
+	// RequestID identifies the failed request for support.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrorViolation is one of the reasons of an error, e.g. an invalid field
+type ErrorViolation struct {
+	Kind        string `json:"kind,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description"`
 }
 
 // ErrorWithDetails outputs an error message with additional details to stderr
@@ -69,6 +144,7 @@ func (s *IOStream) ErrorWithDetails(opts ErrorOptions, args ...interface{}) {
 		s.writeJSON(s.Stderr, opts)
 	} else {
 		sb := strings.Builder{}
+		sb.WriteString("Error: ")
 		sb.WriteString(fmt.Sprintf(opts.Error, args...))
 		if opts.Details != "" {
 			sb.WriteString(fmt.Sprintf(" (%s)", opts.Details))
@@ -76,6 +152,16 @@ func (s *IOStream) ErrorWithDetails(opts ErrorOptions, args ...interface{}) {
 		if opts.Code != "" {
 			sb.WriteString(fmt.Sprintf(" [code: %s]", opts.Code))
 		}
+		for _, v := range opts.Violations {
+			if v.Subject != "" {
+				sb.WriteString(fmt.Sprintf("\n  - %s: %s", v.Subject, v.Description))
+			} else {
+				sb.WriteString(fmt.Sprintf("\n  - %s", v.Description))
+			}
+		}
+		if opts.RequestID != "" {
+			sb.WriteString(fmt.Sprintf("\nRequest ID: %s", opts.RequestID))
+		}
 		fmt.Fprintln(s.Stderr, sb.String())
 	}
 }
@@ -83,6 +169,9 @@ func (s *IOStream) ErrorWithDetails(opts ErrorOptions, args ...interface{}) {
 // ObjectOptions provides configuration options for the Object function
 type ObjectOptions struct {
 	Full bool
+	// NoHeaders omits the header line of tables, for output appended to a
+	// table that was already printed.
+	NoHeaders bool
 }
 
 // Array processes and displays a slice of objects of type T based on the provided configuration
@@ -105,21 +194,19 @@ func (s *IOStream) Array(data interface{}, config []any, opts ObjectOptions) {
 
 	// Extract headers from config
 	headers := make([]string, 0)
+	// Calculate column widths (minimum width = length of header)
+	colWidths := make([]int, 0)
 	for _, cfg := range config {
 		switch c := cfg.(type) {
 		case FieldConfig:
 			if !c.Verbose || opts.Full {
 				headers = append(headers, c.DisplayName)
+				colWidths = append(colWidths, max(len(c.DisplayName), c.Width))
 			}
 		}
 	}
 
 	// Docker CLI-like table
-	// Calculate column widths (minimum width = length of header)
-	colWidths := make([]int, len(headers))
-	for i, h := range headers {
-		colWidths[i] = len(h)
-	}
 
 	// Build rows
 	rows := make([][]string, sliceVal.Len())
@@ -149,13 +236,15 @@ func (s *IOStream) Array(data interface{}, config []any, opts ObjectOptions) {
 	}
 
 	// Print headers
-	for i, h := range headers {
-		if i > 0 {
-			fmt.Fprint(s.Stdout, "  ")
+	if !opts.NoHeaders {
+		for i, h := range headers {
+			if i > 0 {
+				fmt.Fprint(s.Stdout, "  ")
+			}
+			fmt.Fprintf(s.Stdout, "%-*s", colWidths[i], h)
 		}
-		fmt.Fprintf(s.Stdout, "%-*s", colWidths[i], h)
+		fmt.Fprintln(s.Stdout)
 	}
-	fmt.Fprintln(s.Stdout)
 
 	// Print rows
 	for _, row := range rows {
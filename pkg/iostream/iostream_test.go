@@ -16,6 +16,29 @@ type Person struct {
 	Country string `json:"country"`
 }
 
+func personMapping(name, age, country string) []any {
+	return []any{
+		iostream.FieldConfig{
+			DisplayName: name,
+			FormatFunc: func(p Person) string {
+				return p.Name
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: age,
+			FormatFunc: func(p Person) string {
+				return fmt.Sprintf("%d", p.Age)
+			},
+		},
+		iostream.FieldConfig{
+			DisplayName: country,
+			FormatFunc: func(p Person) string {
+				return p.Country
+			},
+		},
+	}
+}
+
 func TestMessagePlainText(t *testing.T) {
 	var stdout bytes.Buffer
 	stream := iostream.New(false)
@@ -55,7 +78,7 @@ func TestErrorPlainText(t *testing.T) {
 
 	stream.Error("Failed with code %d", 404)
 
-	expected := "Failed with code 404\n"
+	expected := "Error: Failed with code 404\n"
 	fmt.Printf("TestErrorPlainText: %q\n", stderr.String())
 	if stderr.String() != expected {
 		t.Errorf("Expected %q, got %q", expected, stderr.String())
@@ -74,7 +97,7 @@ func TestErrorWithDetailsPlainText(t *testing.T) {
 	}
 	stream.ErrorWithDetails(opts, 404)
 
-	expected := "Failed with code 404 (Resource users not found) [code: NOT_FOUND]\n"
+	expected := "Error: Failed with code 404 (Resource users not found) [code: NOT_FOUND]\n"
 	fmt.Printf("TestErrorWithDetailsPlainText: %q\n", stderr.String())
 	if stderr.String() != expected {
 		t.Errorf("Expected %q, got %q", expected, stderr.String())
@@ -123,7 +146,7 @@ func TestObjectPlainText(t *testing.T) {
 		Country: "USA",
 	}
 
-	stream.Object(person)
+	stream.Object(person, personMapping("name", "age", "country"), iostream.ObjectOptions{})
 
 	output := stdout.String()
 	fmt.Printf("TestObjectPlainText:\n%s", output)
@@ -153,7 +176,7 @@ func TestObjectJSON(t *testing.T) {
 		Country: "USA",
 	}
 
-	stream.Object(person)
+	stream.Object(person, personMapping("name", "age", "country"), iostream.ObjectOptions{})
 
 	fmt.Printf("TestObjectJSON: %s", stdout.String())
 	var result Person
@@ -184,18 +207,7 @@ func TestArrayPlainText(t *testing.T) {
 		{Name: "Jane Smith", Age: 28, Country: "Canada"},
 	}
 
-	stream.Array(people, func(key string, value any) (string, string) {
-		switch key {
-		case "name":
-			return "NAME", value.(string)
-		case "age":
-			return "AGE", fmt.Sprintf("%d", value)
-		case "country":
-			return "COUNTRY", fmt.Sprintf("%v", value)
-		default:
-			return "", ""
-		}
-	})
+	stream.Array(people, personMapping("NAME", "AGE", "COUNTRY"), iostream.ObjectOptions{})
 
 	output := stdout.String()
 	fmt.Printf("TestArrayPlainText:\n%s", output)
@@ -236,10 +248,8 @@ func TestArrayJSON(t *testing.T) {
 		{Name: "Jane Smith", Age: 28, Country: "Canada"},
 	}
 
-	stream.Array(people, func(key string, value any) (string, string) {
-		// This function is not used for JSON output
-		return key, fmt.Sprintf("%v", value)
-	})
+	// The mapping is not used for JSON output
+	stream.Array(people, personMapping("NAME", "AGE", "COUNTRY"), iostream.ObjectOptions{})
 
 	fmt.Printf("TestArrayJSON: %s", stdout.String())
 	var result []Person
@@ -259,3 +269,101 @@ func TestArrayJSON(t *testing.T) {
 		t.Errorf("Second item doesn't match: %+v", result[1])
 	}
 }
+
+func TestArrayNoHeaders(t *testing.T) {
+	var stdout bytes.Buffer
+	stream := iostream.New(false)
+	stream.Stdout = &stdout
+
+	people := []Person{{Name: "John Doe", Age: 30, Country: "USA"}}
+	stream.Array(people, personMapping("NAME", "AGE", "COUNTRY"), iostream.ObjectOptions{NoHeaders: true})
+
+	if strings.Contains(stdout.String(), "NAME") {
+		t.Errorf("Expected no headers, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "John Doe") {
+		t.Errorf("Expected the row to be printed, got %q", stdout.String())
+	}
+}
+
+func TestArrayColumnWidth(t *testing.T) {
+	var stdout bytes.Buffer
+	stream := iostream.New(false)
+	stream.Stdout = &stdout
+
+	mapping := personMapping("NAME", "AGE", "COUNTRY")
+	name := mapping[0].(iostream.FieldConfig)
+	name.Width = 12
+	mapping[0] = name
+
+	stream.Array([]Person{{Name: "Jo", Age: 30, Country: "USA"}}, mapping, iostream.ObjectOptions{NoHeaders: true})
+	stream.Array([]Person{{Name: "Jane Smith", Age: 28, Country: "Canada"}}, mapping, iostream.ObjectOptions{NoHeaders: true})
+
+	lines := strings.Split(stdout.String(), "\n")
+	if strings.Index(lines[0], "30") != 14 || strings.Index(lines[1], "28") != 14 {
+		t.Errorf("Expected the rows of separate calls to line up, got %q", stdout.String())
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var stdout bytes.Buffer
+	stream := iostream.New(true)
+	stream.Stdout = &stdout
+
+	people := []Person{
+		{Name: "John Doe", Age: 30, Country: "USA"},
+		{Name: "Jane Smith", Age: 28, Country: "Canada"},
+	}
+	stream.JSONLines(people)
+
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %q", len(lines), stdout.String())
+	}
+	for i, line := range lines {
+		var p Person
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			t.Fatalf("Failed to parse line %d: %v", i, err)
+		}
+		if p != people[i] {
+			t.Errorf("Line %d doesn't match: %+v", i, p)
+		}
+	}
+}
+
+func TestErrorWithViolations(t *testing.T) {
+	var stderr bytes.Buffer
+	stream := iostream.New(false)
+	stream.Stderr = &stderr
+
+	opts := iostream.ErrorOptions{
+		Error: "Creating machine: %s",
+		Code:  "invalid_argument",
+		Violations: []iostream.ErrorViolation{
+			{Kind: "field", Subject: "spec.cpus", Description: "must be a power of two"},
+			{Description: "try again later"},
+		},
+		RequestID: "req-42",
+	}
+	stream.ErrorWithDetails(opts, "invalid spec")
+
+	expected := "Error: Creating machine: invalid spec [code: invalid_argument]\n" +
+		"  - spec.cpus: must be a power of two\n" +
+		"  - try again later\n" +
+		"Request ID: req-42\n"
+	if stderr.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stderr.String())
+	}
+
+	stderr.Reset()
+	stream.JSONOutput = true
+	stream.ErrorWithDetails(opts, "invalid spec")
+
+	var result iostream.ErrorOptions
+	if err := json.Unmarshal(stderr.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(result.Violations) != 2 || result.Violations[0].Subject != "spec.cpus" || result.RequestID != "req-42" {
+		t.Errorf("Unexpected JSON error: %s", stderr.String())
+	}
+}
@@ -102,3 +102,35 @@ func marshalSliceOfProtoMessages(data interface{}) ([]byte, error) {
 	result.WriteString("\n]")
 	return result.Bytes(), nil
 }
+
+// JSONLines writes each element of a slice to stdout as a compact JSON document
+// on its own line (NDJSON), so that output can be consumed as a stream.
+func (s *IOStream) JSONLines(data interface{}) {
+	val := reflect.ValueOf(data)
+	if val.Kind() != reflect.Slice {
+		fmt.Fprintln(s.Stderr, "Error: data is not a slice")
+		return
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		var (
+			line []byte
+			err  error
+		)
+		switch elem := val.Index(i).Interface().(type) {
+		case proto.Message:
+			line, err = protojson.Marshal(elem)
+		default:
+			line, err = json.Marshal(elem)
+		}
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error encoding JSON: %v\n", err)
+			return
+		}
+		line = append(line, '\n')
+		if _, err := s.Stdout.Write(line); err != nil {
+			fmt.Fprintf(s.Stderr, "Error writing JSON: %v\n", err)
+			return
+		}
+	}
+}
@@ -7,6 +7,9 @@ type FieldConfig struct {
 	DisplayName string
 	Verbose     bool
 	FormatFunc  FormatterFunc
+	// Width is the minimum width of the column in tables, so that rows
+	// printed by separate calls line up.
+	Width int
 }
 
 type ObjectConfig struct {
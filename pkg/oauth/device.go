@@ -0,0 +1,215 @@
+// Package oauth implements the OAuth 2.0 device authorization grant (RFC 8628)
+// used by auth login --web, with endpoints found through OpenID Connect
+// discovery.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultInterval is the polling interval when the server does not give
+	// one.
+	defaultInterval = 5 * time.Second
+	// slowDownStep is added to the interval every time the server asks to
+	// slow down.
+	slowDownStep = 5 * time.Second
+)
+
+var (
+	ErrAccessDenied = errors.New("the request was denied")
+	ErrExpired      = errors.New("the code expired before the request was approved")
+)
+
+// Endpoints are the endpoints of an authorization server.
+type Endpoints struct {
+	DeviceAuthorization string `json:"device_authorization_endpoint"`
+	Token               string `json:"token_endpoint"`
+}
+
+// DeviceAuthorization is the code the user approves the login with.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                *int   `json:"interval"`
+}
+
+// Token is an access token issued by the authorization server.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresIn is the lifetime of the token in seconds, 0 when unknown.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// Error is an error response of the authorization server.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+// Client requests tokens for a client ID.
+type Client struct {
+	HTTPClient connect.HTTPClient
+	ClientID   string
+	Endpoints  Endpoints
+	// PollInterval overrides the polling interval of the server when set.
+	PollInterval time.Duration
+}
+
+// Discover fetches the endpoints of issuer from its OpenID Connect discovery
+// document.
+func Discover(ctx context.Context, httpClient connect.HTTPClient, issuer string) (*Endpoints, error) {
+	u := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var endpoints Endpoints
+	if err := do(httpClient, req, &endpoints); err != nil {
+		return nil, fmt.Errorf("discovering the endpoints of %s: %w", issuer, err)
+	}
+	if endpoints.DeviceAuthorization == "" || endpoints.Token == "" {
+		return nil, fmt.Errorf("%s does not support the device authorization grant", issuer)
+	}
+	return &endpoints, nil
+}
+
+// Authorize starts a device authorization, whose user code must then be
+// approved by the user at the verification URI.
+func (c *Client) Authorize(ctx context.Context, scope string) (*DeviceAuthorization, error) {
+	form := url.Values{"client_id": {c.ClientID}}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+
+	var da DeviceAuthorization
+	if err := c.post(ctx, c.Endpoints.DeviceAuthorization, form, &da); err != nil {
+		return nil, err
+	}
+	if da.DeviceCode == "" || da.UserCode == "" || da.VerificationURI == "" {
+		return nil, errors.New("incomplete device authorization response")
+	}
+	return &da, nil
+}
+
+// Wait polls the token endpoint until the user approved or denied da, or until
+// it expired.
+func (c *Client) Wait(ctx context.Context, da *DeviceAuthorization) (*Token, error) {
+	interval := defaultInterval
+	if da.Interval != nil && *da.Interval > 0 {
+		interval = time.Duration(*da.Interval) * time.Second
+	}
+	if c.PollInterval > 0 {
+		interval = c.PollInterval
+	}
+	if da.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(da.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {da.DeviceCode},
+		"client_id":   {c.ClientID},
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrExpired
+			}
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		var token Token
+		err := c.post(ctx, c.Endpoints.Token, form, &token)
+
+		var oauthErr *Error
+		switch {
+		case err == nil:
+			if token.AccessToken == "" {
+				return nil, errors.New("no access token in the token response")
+			}
+			return &token, nil
+		case !errors.As(err, &oauthErr):
+			return nil, err
+		case oauthErr.Code == "authorization_pending":
+		case oauthErr.Code == "slow_down":
+			interval += slowDownStep
+		case oauthErr.Code == "access_denied":
+			return nil, ErrAccessDenied
+		case oauthErr.Code == "expired_token":
+			return nil, ErrExpired
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return do(c.HTTPClient, req, v)
+}
+
+// do sends req and decodes its JSON response into v, or returns the OAuth
+// error of the response.
+func do(httpClient connect.HTTPClient, req *http.Request, v any) error {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType != "application/json" {
+		return fmt.Errorf("unexpected %s response from %s", res.Status, req.URL)
+	}
+	if res.StatusCode != http.StatusOK {
+		oauthErr := &Error{}
+		if err := json.Unmarshal(body, oauthErr); err != nil || oauthErr.Code == "" {
+			return fmt.Errorf("unexpected %s response from %s", res.Status, req.URL)
+		}
+		return oauthErr
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,150 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/oauth"
+)
+
+// authorizationServer approves or denies the device code after a few polls.
+type authorizationServer struct {
+	pending  int
+	outcome  string
+	clientID string
+}
+
+func (s *authorizationServer) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	var base string
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		base = "http://" + r.Host
+		writeJSON(w, http.StatusOK, map[string]string{
+			"device_authorization_endpoint": base + "/device",
+			"token_endpoint":                base + "/token",
+		})
+	})
+	mux.HandleFunc("POST /device", func(w http.ResponseWriter, r *http.Request) {
+		s.clientID = r.FormValue("client_id")
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "dc-1",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": base + "/activate",
+			"expires_in":       60,
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("device_code") != "dc-1" || r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+			t.Errorf("Unexpected token request %v", r.Form)
+		}
+		if s.pending > 0 {
+			s.pending--
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			return
+		}
+		if s.outcome != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": s.outcome})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "at-1", "token_type": "Bearer", "expires_in": 3600})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func login(t *testing.T, s *authorizationServer) (*oauth.Token, error) {
+	t.Helper()
+	srv := httptest.NewServer(s.handler(t))
+	defer srv.Close()
+
+	ctx := context.Background()
+	endpoints, err := oauth.Discover(ctx, srv.Client(), srv.URL+"/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := &oauth.Client{HTTPClient: srv.Client(), ClientID: "baepo-cli", Endpoints: *endpoints, PollInterval: time.Millisecond}
+	da, err := client.Authorize(ctx, "openid")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if da.UserCode != "ABCD-EFGH" || da.VerificationURI != srv.URL+"/activate" {
+		t.Errorf("Unexpected device authorization %+v", da)
+	}
+	return client.Wait(ctx, da)
+}
+
+func TestDeviceFlow(t *testing.T) {
+	s := &authorizationServer{pending: 2}
+	token, err := login(t, s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token.AccessToken != "at-1" {
+		t.Errorf("Expected access token at-1, got %q", token.AccessToken)
+	}
+	if s.pending != 0 {
+		t.Errorf("Expected the token endpoint to be polled until approval, %d polls left", s.pending)
+	}
+	if s.clientID != "baepo-cli" {
+		t.Errorf("Expected client ID baepo-cli, got %q", s.clientID)
+	}
+}
+
+func TestDeviceFlowErrors(t *testing.T) {
+	tests := []struct {
+		outcome  string
+		expected error
+	}{
+		{"access_denied", oauth.ErrAccessDenied},
+		{"expired_token", oauth.ErrExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome, func(t *testing.T) {
+			_, err := login(t, &authorizationServer{pending: 1, outcome: tt.outcome})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	t.Run("invalid_client", func(t *testing.T) {
+		_, err := login(t, &authorizationServer{outcome: "invalid_client"})
+		var oauthErr *oauth.Error
+		if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_client" {
+			t.Errorf("Expected an invalid_client error, got %v", err)
+		}
+	})
+}
+
+func TestWaitIgnoresZeroInterval(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	zero := 0
+	client := &oauth.Client{HTTPClient: srv.Client(), Endpoints: oauth.Endpoints{Token: srv.URL}}
+	if _, err := client.Wait(ctx, &oauth.DeviceAuthorization{DeviceCode: "dc-1", Interval: &zero}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if polls != 0 {
+		t.Errorf("Expected an interval of 0 to fall back to the default one, got %d polls in 100ms", polls)
+	}
+}
@@ -0,0 +1,278 @@
+// Package plugin finds and runs external commands extending the CLI. A plugin
+// is any executable named baepo-<name>, installed in the plugins directory or
+// found on PATH, and is run as `baepo <name>`.
+package plugin
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+)
+
+// Prefix is the prefix of the executables of plugins.
+const Prefix = "baepo-"
+
+const (
+	SourcePlugins = "plugins"
+	SourcePath    = "PATH"
+)
+
+// Plugin is an executable found in the plugins directory or on PATH.
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Source is SourcePlugins or SourcePath.
+	Source string `json:"source"`
+	// ShadowedBy is the path of the plugin taking precedence over this one,
+	// or "built-in" when a command of the CLI has the same name.
+	ShadowedBy string `json:"shadowed_by,omitempty"`
+}
+
+// Dir returns the directory plugins are installed in, ~/.baepo/plugins.
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plugins"), nil
+}
+
+// Find returns the plugin named name. The plugins directory is searched
+// before PATH.
+func Find(name string) (*Plugin, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, false
+	}
+
+	if dir, err := Dir(); err == nil {
+		if p, ok := executable(dir, Prefix+name); ok {
+			return &Plugin{Name: name, Path: p, Source: SourcePlugins}, true
+		}
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if p, ok := executable(dir, Prefix+name); ok {
+			return &Plugin{Name: name, Path: p, Source: SourcePath}, true
+		}
+	}
+	return nil, false
+}
+
+// List returns every plugin of the plugins directory and PATH, in search
+// order. Plugins named after a built-in command, or after a plugin found
+// earlier, are marked as shadowed.
+func List(builtins []string) ([]*Plugin, error) {
+	pluginsDir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{pluginsDir}
+	sources := []string{SourcePlugins}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		dirs = append(dirs, dir)
+		sources = append(sources, SourcePath)
+	}
+
+	var plugins []*Plugin
+	found := map[string]string{}
+	for i, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// Missing or unreadable PATH entries are common, skip them.
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := nameOf(entry.Name())
+			if !ok {
+				continue
+			}
+			p, ok := executable(dir, entry.Name())
+			if !ok {
+				continue
+			}
+
+			plugin := &Plugin{Name: name, Path: p, Source: sources[i]}
+			if slices.Contains(builtins, name) {
+				plugin.ShadowedBy = "built-in"
+			} else if first, ok := found[name]; ok {
+				plugin.ShadowedBy = first
+			} else {
+				found[name] = p
+			}
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	return plugins, nil
+}
+
+// Env returns the environment of a plugin: the one of the CLI, plus the
+// resolved context exported as BAEPO_* variables, credentials included.
+func Env(cfg *config.Config) []string {
+	env := os.Environ()
+	if self, err := os.Executable(); err == nil {
+		env = append(env, "BAEPO_BIN="+self)
+	}
+	if cfg == nil || cfg.CurrentContext == nil {
+		return env
+	}
+
+	c := cfg.CurrentContext
+	env = append(env,
+		"BAEPO_CONTEXT="+cmp.Or(cfg.CurrentContextName, cfg.Context),
+		"BAEPO_URL="+c.URL,
+		"BAEPO_SECRET_KEY="+c.SecretKey,
+		"BAEPO_USER_ID="+c.UserID,
+		"BAEPO_WORKSPACE_ID="+c.WorkspaceID,
+	)
+	// Contexts logged in with auth login --web have no secret key, their
+	// access token is given as BAEPO_TOKEN, unless one is already set.
+	if c.Token == "" && c.AccessToken != "" {
+		env = append(env, "BAEPO_TOKEN="+c.AccessToken)
+	}
+	return env
+}
+
+// Command returns the command running p with args.
+func Command(p *Plugin, args []string, env []string) *exec.Cmd {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// Install copies the executable at src to the plugins directory as plugin
+// name, replacing an installed plugin only when force is set.
+func Install(src, name string, force bool) (*Plugin, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating plugins directory: %w", err)
+	}
+
+	dst := filepath.Join(dir, Prefix+name+exeSuffix())
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(dst, flags, 0755)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("plugin %q is already installed at %s", name, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	// O_TRUNC keeps the mode of a replaced file.
+	if err := os.Chmod(dst, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Plugin{Name: name, Path: dst, Source: SourcePlugins}, nil
+}
+
+// Remove deletes plugin name from the plugins directory. Plugins found on
+// PATH are not managed by the CLI and are left alone.
+func Remove(name string) (*Plugin, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := executable(dir, Prefix+name)
+	if !ok {
+		if other, ok := Find(name); ok {
+			return nil, fmt.Errorf("plugin %q was not installed with baepo plugin install, remove %s instead", name, other.Path)
+		}
+		return nil, fmt.Errorf("plugin %q is not installed", name)
+	}
+
+	if err := os.Remove(p); err != nil {
+		return nil, err
+	}
+	return &Plugin{Name: name, Path: p, Source: SourcePlugins}, nil
+}
+
+// ValidateName checks that name can be run as `baepo <name>`.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("plugin name must not be empty")
+	}
+	if strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\ `) {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+	return nil
+}
+
+// NameFromPath returns the plugin name of an executable, baepo-deploy.sh
+// being plugin deploy.
+func NameFromPath(p string) string {
+	base := filepath.Base(p)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return strings.TrimPrefix(base, Prefix)
+}
+
+// nameOf returns the plugin name of a file in a plugin directory.
+func nameOf(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+// executable returns the path of file in dir if it is an executable file.
+func executable(dir, file string) (string, bool) {
+	if dir == "" {
+		return "", false
+	}
+	p, err := exec.LookPath(filepath.Join(dir, file))
+	if err != nil {
+		return "", false
+	}
+	if info, err := os.Stat(p); err != nil || info.IsDir() {
+		return "", false
+	}
+	return p, true
+}
+
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/config"
+	"github.com/baepo-cloud/baepo-cli/pkg/plugin"
+)
+
+// setup isolates HOME and PATH, and returns the plugins directory and a
+// directory on PATH.
+func setup(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	home := t.TempDir()
+	bin := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin)
+
+	dir, err := plugin.Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir, bin
+}
+
+func writeScript(t *testing.T, dir, name string, mode os.FileMode) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte("#!/bin/sh\necho \"$BAEPO_CONTEXT $*\"\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestFind(t *testing.T) {
+	dir, bin := setup(t)
+	installed := writeScript(t, dir, "baepo-deploy", 0755)
+	writeScript(t, bin, "baepo-deploy", 0755)
+	onPath := writeScript(t, bin, "baepo-cost", 0755)
+	writeScript(t, bin, "baepo-notes", 0644)
+
+	p, ok := plugin.Find("deploy")
+	if !ok || p.Path != installed || p.Source != plugin.SourcePlugins {
+		t.Errorf("Expected deploy from the plugins directory, got %+v", p)
+	}
+	p, ok = plugin.Find("cost")
+	if !ok || p.Path != onPath || p.Source != plugin.SourcePath {
+		t.Errorf("Expected cost from PATH, got %+v", p)
+	}
+	if p, ok := plugin.Find("notes"); ok {
+		t.Errorf("Expected non executable files to be ignored, got %+v", p)
+	}
+	if p, ok := plugin.Find("../baepo-deploy"); ok {
+		t.Errorf("Expected paths to be rejected, got %+v", p)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir, bin := setup(t)
+	installed := writeScript(t, dir, "baepo-deploy", 0755)
+	writeScript(t, bin, "baepo-deploy", 0755)
+	writeScript(t, bin, "baepo-machine", 0755)
+
+	plugins, err := plugin.List([]string{"machine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shadowedBy := map[string]string{}
+	for _, p := range plugins {
+		shadowedBy[p.Source+" "+p.Name] = p.ShadowedBy
+	}
+	expected := map[string]string{
+		"plugins deploy": "",
+		"PATH deploy":    installed,
+		"PATH machine":   "built-in",
+	}
+	if len(shadowedBy) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, shadowedBy)
+	}
+	for k, v := range expected {
+		if shadowedBy[k] != v {
+			t.Errorf("Expected %s to be shadowed by %q, got %q", k, v, shadowedBy[k])
+		}
+	}
+}
+
+func TestInstallRemove(t *testing.T) {
+	_, bin := setup(t)
+	src := writeScript(t, t.TempDir(), "deploy.sh", 0644)
+
+	p, err := plugin.Install(src, plugin.NameFromPath(src), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "deploy" {
+		t.Errorf("Expected plugin deploy, got %s", p.Name)
+	}
+	if found, ok := plugin.Find("deploy"); !ok || found.Path != p.Path {
+		t.Errorf("Expected the installed plugin to be found, got %+v", found)
+	}
+
+	if _, err := plugin.Install(src, "deploy", false); err == nil {
+		t.Error("Expected installing over a plugin to fail without force")
+	}
+	if _, err := plugin.Install(src, "deploy", true); err != nil {
+		t.Errorf("Expected force to replace the plugin, got %v", err)
+	}
+
+	if _, err := plugin.Remove("deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plugin.Find("deploy"); ok {
+		t.Error("Expected the plugin to be removed")
+	}
+
+	writeScript(t, bin, "baepo-cost", 0755)
+	if _, err := plugin.Remove("cost"); err == nil || !strings.Contains(err.Error(), "not installed with baepo plugin install") {
+		t.Errorf("Expected plugins on PATH to be left alone, got %v", err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	cfg := &config.Config{
+		Context: "prod",
+		CurrentContext: &config.Context{
+			URL:         "https://api.baepo.cloud/",
+			SecretKey:   "sk",
+			WorkspaceID: "w1",
+		},
+	}
+
+	env := plugin.Env(cfg)
+	for _, expected := range []string{"BAEPO_CONTEXT=prod", "BAEPO_URL=https://api.baepo.cloud/", "BAEPO_SECRET_KEY=sk", "BAEPO_WORKSPACE_ID=w1"} {
+		found := false
+		for _, kv := range env {
+			found = found || kv == expected
+		}
+		if !found {
+			t.Errorf("Expected %s in the environment", expected)
+		}
+	}
+}
+
+func TestEnvSelectedContext(t *testing.T) {
+	cfg := &config.Config{
+		Context:            "default",
+		CurrentContextName: "prod",
+		CurrentContext:     &config.Context{URL: "https://api.baepo.cloud/"},
+	}
+	if env := plugin.Env(cfg); !slices.Contains(env, "BAEPO_CONTEXT=prod") {
+		t.Errorf("Expected the context selected with --context to be exported, got %v", env)
+	}
+}
+
+func TestEnvAccessToken(t *testing.T) {
+	t.Setenv("BAEPO_TOKEN", "")
+	cfg := &config.Config{
+		Context:        "sso",
+		CurrentContext: &config.Context{UserID: "u1", AccessToken: "at-1"},
+	}
+	if env := plugin.Env(cfg); !slices.Contains(env, "BAEPO_TOKEN=at-1") {
+		t.Errorf("Expected the access token to be exported as BAEPO_TOKEN, got %v", env)
+	}
+
+	cfg.CurrentContext.Token = "tok-ci"
+	if env := plugin.Env(cfg); slices.Contains(env, "BAEPO_TOKEN=at-1") {
+		t.Errorf("Expected BAEPO_TOKEN to take precedence over the access token, got %v", env)
+	}
+}
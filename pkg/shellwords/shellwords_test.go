@@ -0,0 +1,41 @@
+package shellwords_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/baepo-cloud/baepo-cli/pkg/shellwords"
+)
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		``:                              nil,
+		`redis-server --save ''`:        {"redis-server", "--save", ""},
+		`sh -c "echo \"hi\" && ls"`:     {"sh", "-c", `echo "hi" && ls`},
+		`echo 'single $HOME' "$HOME"`:   {"echo", "single $HOME", "$HOME"},
+		`a\ b  c`:                       {"a b", "c"},
+		"one \\\n two":                  {"one", "two"},
+		`--env="A=1"`:                   {"--env=A=1"},
+		"  leading and trailing  \t":    {"leading", "and", "trailing"},
+		`/usr/bin/startup.sh --port=80`: {"/usr/bin/startup.sh", "--port=80"},
+	}
+
+	for input, expected := range tests {
+		words, err := shellwords.Split(input)
+		if err != nil {
+			t.Errorf("Split(%q) returned an error: %v", input, err)
+			continue
+		}
+		if !reflect.DeepEqual(words, expected) {
+			t.Errorf("Split(%q) = %q, expected %q", input, words, expected)
+		}
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	for _, input := range []string{`'open`, `"open`, `trailing\`} {
+		if _, err := shellwords.Split(input); err == nil {
+			t.Errorf("Split(%q) expected an error", input)
+		}
+	}
+}